@@ -3,10 +3,12 @@ package cmd
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/pPrecel/PKUP/internal/logo"
 	"github.com/pPrecel/PKUP/pkg/compose"
+	"github.com/pPrecel/PKUP/pkg/compose/utils"
 	"github.com/pPrecel/PKUP/pkg/config"
 	"github.com/pPrecel/PKUP/pkg/period"
 	"github.com/pPrecel/PKUP/pkg/report"
@@ -27,7 +29,7 @@ func NewComposeCommand(opts *Options) *cli.Command {
 		Name:      "compose",
 		Usage:     "Generates .diff and report files for many users and based on the .yaml config file",
 		UsageText: "pkup gen --config .pkupcompose.yaml",
-		Flags: []cli.Flag{
+		Flags: withSetupErrors(&actionsOpts.output, []cli.Flag{
 			&cli.StringFlag{
 				Name:        "config",
 				Value:       ".pkupcompose.yaml",
@@ -42,6 +44,11 @@ func NewComposeCommand(opts *Options) *cli.Command {
 					return nil
 				},
 			},
+			&cli.StringFlag{
+				Name:        "state",
+				Usage:       "path to the file with users and repos completed before the interruption ( default: <CONFIG>.state.json )",
+				Destination: &actionsOpts.state,
+			},
 			&cli.TimestampFlag{
 				Name:     "since",
 				Usage:    "timestamp used to get commits and render report - foramt " + report.PeriodFormat,
@@ -62,6 +69,51 @@ func NewComposeCommand(opts *Options) *cli.Command {
 					return nil
 				},
 			},
+			&cli.IntFlag{
+				Name:  "parallelism",
+				Usage: fmt.Sprintf("max number of concurrent API calls ( default: %d )", utils.DefaultParallelism),
+				Action: func(_ *cli.Context, parallelism int) error {
+					if parallelism < 1 {
+						return fmt.Errorf("parallelism '%d' must be greater than 0", parallelism)
+					}
+
+					actionsOpts.parallelism = parallelism
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:  "failure-policy",
+				Usage: failurePolicyUsage,
+				Action: func(_ *cli.Context, policy string) error {
+					if err := compose.ValidateFailurePolicy(policy); err != nil {
+						return err
+					}
+
+					actionsOpts.failurePolicy = policy
+					return nil
+				},
+			},
+			&cli.BoolFlag{
+				Name:        "dry-run",
+				Usage:       "resolve repos, branches, signatures and count commits for all users without writing any file",
+				Destination: &actionsOpts.dryRun,
+			},
+			&cli.StringFlag{
+				Name:        "output-format",
+				Usage:       outputFormatUsage,
+				Value:       outputFormatText,
+				Category:    loggingCategory,
+				Destination: &actionsOpts.output.format,
+				Action: func(_ *cli.Context, format string) error {
+					return validateOutputFormat(format)
+				},
+			},
+			&cli.StringFlag{
+				Name:        "output-file",
+				Usage:       outputFileUsage,
+				Category:    loggingCategory,
+				Destination: &actionsOpts.output.file,
+			},
 			&cli.BoolFlag{
 				Name:        "ci",
 				Usage:       "print output using standard log",
@@ -88,20 +140,45 @@ func NewComposeCommand(opts *Options) *cli.Command {
 					return nil
 				},
 			},
-		},
+		}),
 		Before: func(_ *cli.Context) error {
+			// keep the stdout for the JSON output
+			redirectHumanOutput(&actionsOpts.output, opts.Log)
+
 			// print logo before any action
-			fmt.Printf("%s\n\n", logo.Build(opts.BuildVersion))
+			fmt.Fprintf(actionsOpts.output.humanOutput(), "%s\n\n", logo.Build(opts.BuildVersion))
 
 			return nil
 		},
+		After: func(ctx *cli.Context) error {
+			return writeSetupOutput(&actionsOpts.output, runOutput{
+				Command: ctx.Command.Name,
+				DryRun:  actionsOpts.dryRun,
+				Since:   *actionsOpts.since.Value(),
+				Until:   *actionsOpts.until.Value(),
+			})
+		},
 		Action: func(ctx *cli.Context) error {
-			return composeCommandAction(ctx, &actionsOpts)
+			start := time.Now()
+			summary, err := composeCommandAction(ctx, &actionsOpts)
+
+			outputErr := writeRunOutput(&actionsOpts.output, runOutput{
+				Command:         ctx.Command.Name,
+				DryRun:          actionsOpts.dryRun,
+				Since:           *actionsOpts.since.Value(),
+				Until:           *actionsOpts.until.Value(),
+				DurationSeconds: time.Since(start).Seconds(),
+			}, summary, ctx.Context.Err() != nil, err)
+			if outputErr != nil {
+				return outputErr
+			}
+
+			return err
 		},
 	}
 }
 
-func composeCommandAction(ctx *cli.Context, opts *composeActionOpts) error {
+func composeCommandAction(ctx *cli.Context, opts *composeActionOpts) (*compose.Summary, error) {
 	opts.Log.Info("generating report for the PKUP period", opts.Log.Args(
 		"config", opts.config,
 		"since", opts.since.Value().Local().Format(logTimeFormat),
@@ -110,18 +187,37 @@ func composeCommandAction(ctx *cli.Context, opts *composeActionOpts) error {
 
 	cfg, err := config.Read(opts.config)
 	if err != nil {
-		return fmt.Errorf("failed to read config from path '%s': %s", opts.config, err.Error())
+		return nil, fmt.Errorf("failed to read config from path '%s': %s", opts.config, err.Error())
+	}
+
+	statePath := opts.state
+	if statePath == "" {
+		statePath = strings.TrimSuffix(opts.config, filepath.Ext(opts.config)) + ".state.json"
+	}
+
+	summary, err := compose.New(ctx.Context, opts.Log).ForConfig(cfg, compose.Options{
+		Since:         *opts.since.Value(),
+		Until:         *opts.until.Value(),
+		Ci:            opts.ci,
+		Parallelism:   opts.parallelism,
+		StatePath:     statePath,
+		DryRun:        opts.dryRun,
+		FailurePolicy: opts.failurePolicy,
+	})
+	if summary != nil {
+		if printErr := printSummary(summary); printErr != nil {
+			return summary, printErr
+		}
+	}
+	if err != nil {
+		return summary, err
 	}
 
-	if err := compose.New(ctx.Context, opts.Log).ForConfig(cfg, compose.Options{
-		Since: *opts.since.Value(),
-		Until: *opts.until.Value(),
-		Ci:    opts.ci,
-	}); err != nil {
-		return err
+	if err := summaryExitError(summary); err != nil {
+		return summary, err
 	}
 
-	fmt.Println(logo.ClaudeTip())
+	fmt.Fprintln(opts.output.humanOutput(), logo.ClaudeTip())
 
-	return nil
+	return summary, nil
 }
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pPrecel/PKUP/pkg/report"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// path to the user config relative to the user config dir ( e.g. $XDG_CONFIG_HOME )
+	userConfigPath = "pkup/config.yaml"
+	// prefix of env variables with flags values ( e.g. PKUP_USERNAME, PKUP_REPORT_FIELD )
+	envVarsPrefix = "PKUP_"
+	maskedToken   = "***"
+)
+
+// genDefaults are gen options saved in the user config file
+// keys are the same as flag names
+type genDefaults struct {
+	Username      string   `yaml:"username,omitempty"`
+	Repos         []string `yaml:"repo,omitempty"`
+	Orgs          []string `yaml:"org,omitempty"`
+	Since         string   `yaml:"since,omitempty"`
+	Until         string   `yaml:"until,omitempty"`
+	EnterpriseURL string   `yaml:"enterprise-url,omitempty"`
+	Token         string   `yaml:"token,omitempty"`
+	Output        string   `yaml:"output,omitempty"`
+	Template      string   `yaml:"template,omitempty"`
+	Formats       []string `yaml:"format,omitempty"`
+	GroupBy       string   `yaml:"group-by,omitempty"`
+	Locales       []string `yaml:"locale,omitempty"`
+	PDFLayout     string   `yaml:"pdf-layout,omitempty"`
+	Validation    string   `yaml:"validation,omitempty"`
+	ReportFields  []string `yaml:"report-field,omitempty"`
+	Enrich        bool     `yaml:"enrich,omitempty"`
+	Bots          string   `yaml:"bots,omitempty"`
+	BotLogins     []string `yaml:"bot-login,omitempty"`
+	AllBranches   bool     `yaml:"all-branches,omitempty"`
+	UniqueOnly    bool     `yaml:"unique-only,omitempty"`
+	Interactive   bool     `yaml:"interactive,omitempty"`
+	Parallelism   int      `yaml:"parallelism,omitempty"`
+	FailurePolicy string   `yaml:"failure-policy,omitempty"`
+	Ci            bool     `yaml:"ci,omitempty"`
+}
+
+func defaultUserConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, userConfigPath)
+}
+
+// withEnvVars allows to set every flag with the PKUP_<FLAG_NAME> env variable
+func withEnvVars(flags []cli.Flag) []cli.Flag {
+	for _, flag := range flags {
+		envVars := []string{flagEnvVar(flag.Names()[0])}
+		switch f := flag.(type) {
+		case *cli.StringFlag:
+			f.EnvVars = envVars
+		case *cli.StringSliceFlag:
+			f.EnvVars = envVars
+		case *cli.BoolFlag:
+			f.EnvVars = envVars
+		case *cli.IntFlag:
+			f.EnvVars = envVars
+		case *cli.TimestampFlag:
+			f.EnvVars = envVars
+		}
+	}
+
+	return flags
+}
+
+func flagEnvVar(name string) string {
+	return envVarsPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// applyUserConfig sets flags from the user config file if they were not set by args or env variables
+// returns error when the file does not exist only if it's required
+func applyUserConfig(ctx *cli.Context, path string, required bool) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read user config: %s", err.Error())
+	}
+
+	defaults := genDefaults{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&defaults); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to decode user config '%s': %s", path, err.Error())
+	}
+
+	values, err := toFlagValues(defaults)
+	if err != nil {
+		return err
+	}
+
+	for name, vals := range values {
+		if ctx.IsSet(name) {
+			// args and env variables take precedence
+			continue
+		}
+
+		for _, val := range vals {
+			if err := ctx.Set(name, expandHome(val)); err != nil {
+				return fmt.Errorf("failed to set '%s' from user config: %s", name, err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// toFlagValues returns non-empty values of defaults by flag names
+func toFlagValues(defaults genDefaults) (map[string][]string, error) {
+	data, err := yaml.Marshal(defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	values := map[string][]string{}
+	for name, field := range fields {
+		switch v := field.(type) {
+		case []interface{}:
+			for _, item := range v {
+				values[name] = append(values[name], fmt.Sprint(item))
+			}
+		case bool:
+			values[name] = []string{strconv.FormatBool(v)}
+		default:
+			values[name] = []string{fmt.Sprint(v)}
+		}
+	}
+
+	return values, nil
+}
+
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(home, path[2:])
+}
+
+// effectiveDefaults returns options merged from flags, env variables and the user config
+// token is masked
+func (opts *genActionOpts) effectiveDefaults() genDefaults {
+	defaults := genDefaults{
+		Username:      opts.username,
+		Repos:         opts.repos,
+		Orgs:          opts.orgs,
+		Since:         opts.since.Value().Format(report.PeriodFormat),
+		Until:         opts.until.Value().Format(report.PeriodFormat),
+		EnterpriseURL: opts.enterpriseURL,
+		Output:        opts.outputDir,
+		Template:      opts.templatePath,
+		Formats:       opts.formats,
+		GroupBy:       opts.groupBy,
+		Locales:       opts.locales,
+		PDFLayout:     opts.pdfLayoutPath,
+		Validation:    opts.validationMode,
+		Enrich:        opts.enrich,
+		Bots:          opts.botsMode,
+		BotLogins:     opts.botLogins,
+		AllBranches:   opts.allBranches,
+		UniqueOnly:    opts.uniqueOnly,
+		Interactive:   opts.interactive,
+		Parallelism:   opts.parallelism,
+		FailurePolicy: opts.failurePolicy,
+		Ci:            opts.ci,
+	}
+
+	if opts.token != "" {
+		defaults.Token = maskedToken
+	}
+
+	for key, value := range opts.reportFields {
+		defaults.ReportFields = append(defaults.ReportFields, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(defaults.ReportFields)
+
+	return defaults
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_applyUserConfig(t *testing.T) {
+	t.Run("use values from the user config", func(t *testing.T) {
+		userConfig := fixUserConfig(t, "username: config-user\n"+
+			"org:\n- config-org1\n- config-org2\n"+
+			"report-field:\n- pkupGenEmployeesName=John\n- pkupGenJobTitle=Dev\n"+
+			"template: ~/template.docx\n"+
+			"parallelism: 3\n"+
+			"enrich: true\n")
+
+		got, err := runGenCommand(t, "--user-config", userConfig)
+		require.NoError(t, err)
+		require.Equal(t, "config-user", got.String("username"))
+		require.Equal(t, []string{"config-org1", "config-org2"}, got.StringSlice("org"))
+		require.Equal(t, []string{"pkupGenEmployeesName=John", "pkupGenJobTitle=Dev"}, got.StringSlice("report-field"))
+		require.Equal(t, filepath.Join(os.Getenv("HOME"), "template.docx"), got.String("template"))
+		require.Equal(t, 3, got.Int("parallelism"))
+		require.True(t, got.Bool("enrich"))
+	})
+
+	t.Run("env variables take precedence over the user config", func(t *testing.T) {
+		userConfig := fixUserConfig(t, "username: config-user\norg:\n- config-org\n")
+		t.Setenv("PKUP_USERNAME", "env-user")
+		t.Setenv("PKUP_ORG", "env-org1,env-org2")
+
+		got, err := runGenCommand(t, "--user-config", userConfig)
+		require.NoError(t, err)
+		require.Equal(t, "env-user", got.String("username"))
+		require.Equal(t, []string{"env-org1", "env-org2"}, got.StringSlice("org"))
+	})
+
+	t.Run("flags take precedence over env variables and the user config", func(t *testing.T) {
+		userConfig := fixUserConfig(t, "username: config-user\nreport-field:\n- pkupGenJobTitle=Dev\n")
+		t.Setenv("PKUP_USERNAME", "env-user")
+
+		got, err := runGenCommand(t,
+			"--user-config", userConfig,
+			"--username", "flag-user",
+			"--report-field", "pkupGenDepartment=IT",
+		)
+		require.NoError(t, err)
+		require.Equal(t, "flag-user", got.String("username"))
+		// slices are replaced, not merged
+		require.Equal(t, []string{"pkupGenDepartment=IT"}, got.StringSlice("report-field"))
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		userConfig := fixUserConfig(t, "username: config-user\nrepos:\n- org/repo\n")
+
+		_, err := runGenCommand(t, "--user-config", userConfig)
+		require.ErrorContains(t, err, "failed to decode user config")
+		require.ErrorContains(t, err, "field repos not found")
+	})
+
+	t.Run("skip missing default user config", func(t *testing.T) {
+		got, err := runGenCommand(t, "--username", "flag-user")
+		require.NoError(t, err)
+		require.Equal(t, "flag-user", got.String("username"))
+	})
+
+	t.Run("missing user config set by flag", func(t *testing.T) {
+		_, err := runGenCommand(t, "--user-config", filepath.Join(t.TempDir(), "missing.yaml"))
+		require.ErrorContains(t, err, "failed to read user config")
+	})
+}
+
+func Test_genActionOpts_effectiveDefaults(t *testing.T) {
+	opts := &genActionOpts{
+		since:        *cli.NewTimestamp(time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)),
+		until:        *cli.NewTimestamp(time.Date(2024, 2, 18, 23, 59, 59, 0, time.UTC)),
+		username:     "test-user",
+		token:        "secret",
+		orgs:         []string{"test-org"},
+		parallelism:  3,
+		reportFields: map[string]string{"pkupGenJobTitle": "Dev", "pkupGenEmployeesName": "John"},
+	}
+
+	require.Equal(t, genDefaults{
+		Username:     "test-user",
+		Token:        maskedToken,
+		Orgs:         []string{"test-org"},
+		Since:        "19.01.2024",
+		Until:        "18.02.2024",
+		Parallelism:  3,
+		ReportFields: []string{"pkupGenEmployeesName=John", "pkupGenJobTitle=Dev"},
+	}, opts.effectiveDefaults())
+}
+
+func Test_printOptions(t *testing.T) {
+	t.Run("print only the user config", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		stdout := os.Stdout
+		os.Stdout = w
+		defer func() {
+			os.Stdout = stdout
+		}()
+
+		app := &cli.App{
+			Commands: []*cli.Command{
+				NewGenCommand(&Options{
+					Log: pterm.DefaultLogger.WithWriter(io.Discard),
+				}),
+			},
+		}
+		err = app.Run([]string{"pkup", "gen", "--print-options", "--username", "test-user", "--token", "secret"})
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		out, err := io.ReadAll(r)
+		require.NoError(t, err)
+
+		printed := genDefaults{}
+		decoder := yaml.NewDecoder(bytes.NewReader(out))
+		decoder.KnownFields(true)
+		require.NoError(t, decoder.Decode(&printed))
+		require.Equal(t, "test-user", printed.Username)
+		require.Equal(t, maskedToken, printed.Token)
+	})
+}
+
+// runGenCommand runs the gen command without its action and returns the context with resolved flags
+func runGenCommand(t *testing.T, args ...string) (*cli.Context, error) {
+	// don't read the real user config and home dir
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	stdout := os.Stdout
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	require.NoError(t, err)
+	os.Stdout = devNull
+	t.Cleanup(func() {
+		os.Stdout = stdout
+		devNull.Close()
+	})
+
+	var got *cli.Context
+	command := NewGenCommand(&Options{
+		Log: pterm.DefaultLogger.WithWriter(io.Discard),
+	})
+	command.Action = func(ctx *cli.Context) error {
+		got = ctx
+		return nil
+	}
+
+	app := &cli.App{
+		Writer:    io.Discard,
+		ErrWriter: io.Discard,
+		Commands:  []*cli.Command{command},
+	}
+
+	err = app.Run(append([]string{"pkup", "gen"}, args...))
+	return got, err
+}
+
+func fixUserConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	return path
+}
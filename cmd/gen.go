@@ -5,16 +5,21 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/pPrecel/PKUP/internal/logo"
 	"github.com/pPrecel/PKUP/internal/token"
+	"github.com/pPrecel/PKUP/internal/view"
 	"github.com/pPrecel/PKUP/pkg/compose"
+	"github.com/pPrecel/PKUP/pkg/compose/utils"
 	"github.com/pPrecel/PKUP/pkg/config"
+	"github.com/pPrecel/PKUP/pkg/github"
 	"github.com/pPrecel/PKUP/pkg/period"
 	"github.com/pPrecel/PKUP/pkg/report"
 	"github.com/pterm/pterm"
 	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -38,7 +43,7 @@ func NewGenCommand(opts *Options) *cli.Command {
 			"\t\t--repo <org1>/<repo1> \\\n" +
 			"\t\t--repo <org2>/<repo2>",
 		Aliases: []string{"g", "generate", "get"},
-		Flags: []cli.Flag{
+		Flags: withSetupErrors(&actionsOpts.output, withEnvVars([]cli.Flag{
 			&cli.StringSliceFlag{
 				Name:  "repo",
 				Usage: "<org>/<repo> slice - use this flag to look for user activity in specified repos",
@@ -57,8 +62,7 @@ func NewGenCommand(opts *Options) *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:        "username",
-				Usage:       "GitHub user name",
-				Required:    true,
+				Usage:       "GitHub user name ( required )",
 				Destination: &actionsOpts.username,
 			},
 			&cli.TimestampFlag{
@@ -129,7 +133,7 @@ func NewGenCommand(opts *Options) *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:    "template",
-				Usage:   "full path to the docx template - if not set program generates .txt data file",
+				Usage:   "full path to the template (.docx, .odt or go template .tmpl/.txt/.md) - if not set program generates .txt data file",
 				Aliases: []string{"tmpl"},
 				Action: func(_ *cli.Context, path string) error {
 					path, err := filepath.Abs(filepath.Clean(path))
@@ -145,6 +149,72 @@ func NewGenCommand(opts *Options) *cli.Command {
 					return nil
 				},
 			},
+			&cli.StringSliceFlag{
+				Name:  "format",
+				Usage: "additional report format - one of: " + strings.Join(report.Formats, ", "),
+				Action: func(_ *cli.Context, formats []string) error {
+					for _, format := range formats {
+						if err := report.ValidateFormat(format); err != nil {
+							return err
+						}
+					}
+
+					actionsOpts.formats = formats
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:  "group-by",
+				Usage: "split report results into sections - one of: " + strings.Join(report.GroupByOptions, ", "),
+				Action: func(_ *cli.Context, groupBy string) error {
+					if err := report.ValidateGroupBy(groupBy); err != nil {
+						return err
+					}
+
+					actionsOpts.groupBy = groupBy
+					return nil
+				},
+			},
+			&cli.StringSliceFlag{
+				Name:  "locale",
+				Usage: "report language - one of: " + strings.Join(report.Locales, ", ") + " ( every language is saved to separate files when used many times )",
+				Action: func(_ *cli.Context, locales []string) error {
+					for _, locale := range locales {
+						if err := report.ValidateLocale(locale); err != nil {
+							return err
+						}
+					}
+
+					actionsOpts.locales = locales
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:  "pdf-layout",
+				Usage: "full path to the yaml file with the pdf report layout - used with '--format pdf'",
+				Action: func(_ *cli.Context, path string) error {
+					path, err := filepath.Abs(filepath.Clean(path))
+					if err != nil {
+						return err
+					}
+
+					actionsOpts.pdfLayoutPath = path
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:  "validation",
+				Usage: "what to do when the rendered report contains unreplaced placeholders or unused report fields - one of: " + strings.Join(report.ValidationModes, ", "),
+				Value: report.ValidationModeWarn,
+				Action: func(_ *cli.Context, mode string) error {
+					if err := report.ValidateValidationMode(mode); err != nil {
+						return err
+					}
+
+					actionsOpts.validationMode = mode
+					return nil
+				},
+			},
 			&cli.StringSliceFlag{
 				Name:  "report-field",
 				Usage: "custom field that will be replace in the output report - in format FIELD=VALUE",
@@ -154,6 +224,34 @@ func NewGenCommand(opts *Options) *cli.Command {
 					return err
 				},
 			},
+			&cli.BoolFlag{
+				Name:  "enrich",
+				Usage: "fetch pull requests associated with commits and issues closed by them ( additional API calls for every commit )",
+				Action: func(_ *cli.Context, b bool) error {
+					actionsOpts.enrich = b
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:  "bots",
+				Usage: "what to do with commits created by bot accounts ( e.g. dependabot commits co-authored by the user ) - one of: " + strings.Join(github.BotCommitsModes, ", "),
+				Action: func(_ *cli.Context, mode string) error {
+					if err := github.ValidateBotCommitsMode(mode); err != nil {
+						return err
+					}
+
+					actionsOpts.botsMode = mode
+					return nil
+				},
+			},
+			&cli.StringSliceFlag{
+				Name:  "bot-login",
+				Usage: "additional login or name of the bot account ( accounts with the '[bot]' suffix are always detected )",
+				Action: func(_ *cli.Context, logins []string) error {
+					actionsOpts.botLogins = logins
+					return nil
+				},
+			},
 			&cli.BoolFlag{
 				Name:  "all-branches",
 				Usage: "search in all branches ( use with '--unique-only' to redice noise )",
@@ -170,6 +268,70 @@ func NewGenCommand(opts *Options) *cli.Command {
 					return nil
 				},
 			},
+			&cli.BoolFlag{
+				Name:  "interactive",
+				Usage: "select commits and edit their descriptions before saving any file",
+				Action: func(_ *cli.Context, b bool) error {
+					actionsOpts.interactive = b
+					return nil
+				},
+			},
+			&cli.IntFlag{
+				Name:  "parallelism",
+				Usage: fmt.Sprintf("max number of concurrent API calls ( default: %d )", utils.DefaultParallelism),
+				Action: func(_ *cli.Context, parallelism int) error {
+					if parallelism < 1 {
+						return fmt.Errorf("parallelism '%d' must be greater than 0", parallelism)
+					}
+
+					actionsOpts.parallelism = parallelism
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:  "failure-policy",
+				Usage: failurePolicyUsage,
+				Action: func(_ *cli.Context, policy string) error {
+					if err := compose.ValidateFailurePolicy(policy); err != nil {
+						return err
+					}
+
+					actionsOpts.failurePolicy = policy
+					return nil
+				},
+			},
+			&cli.BoolFlag{
+				Name:        "dry-run",
+				Usage:       "resolve repos, branches, signatures and count commits without writing any file",
+				Destination: &actionsOpts.dryRun,
+			},
+			&cli.StringFlag{
+				Name:        "user-config",
+				Usage:       "path to the yaml file with default values of flags ( keys are flag names ) - flags and PKUP_<FLAG_NAME> env variables take precedence",
+				Value:       defaultUserConfigPath(),
+				Destination: &actionsOpts.userConfig,
+			},
+			&cli.BoolFlag{
+				Name:        "print-options",
+				Usage:       "print options merged from flags, env variables and the user config in the user config format and exit",
+				Destination: &actionsOpts.printOptions,
+			},
+			&cli.StringFlag{
+				Name:        "output-format",
+				Usage:       outputFormatUsage,
+				Value:       outputFormatText,
+				Category:    loggingCategory,
+				Destination: &actionsOpts.output.format,
+				Action: func(_ *cli.Context, format string) error {
+					return validateOutputFormat(format)
+				},
+			},
+			&cli.StringFlag{
+				Name:        "output-file",
+				Usage:       outputFileUsage,
+				Category:    loggingCategory,
+				Destination: &actionsOpts.output.file,
+			},
 			&cli.BoolFlag{
 				Name:     "ci",
 				Usage:    "print output using standard log",
@@ -199,31 +361,80 @@ func NewGenCommand(opts *Options) *cli.Command {
 					return nil
 				},
 			},
-		},
-		Before: func(_ *cli.Context) error {
-			// print logo before any action
-			fmt.Printf("%s\n\n", logo.Build(opts.BuildVersion))
+		})),
+		Before: func(ctx *cli.Context) error {
+			// keep the stdout for the JSON output
+			redirectHumanOutput(&actionsOpts.output, opts.Log)
+
+			if !actionsOpts.printOptions {
+				// print logo before any action ( printed options must be a valid user config )
+				fmt.Fprintf(actionsOpts.output.humanOutput(), "%s\n\n", logo.Build(opts.BuildVersion))
+			}
+
+			// user defaults for flags not set by args and env variables
+			if err := applyUserConfig(ctx, actionsOpts.userConfig, ctx.IsSet("user-config")); err != nil {
+				return actionsOpts.output.setupFailed(err)
+			}
 
 			// default
 			if err := actionsOpts.setDefaults(); err != nil {
-				return err
+				return actionsOpts.output.setupFailed(err)
 			}
 
 			// validate
 			if actionsOpts.enterpriseURL != "" && actionsOpts.token == "" {
-				return errors.New("specify token when using enterprise url")
+				return actionsOpts.output.setupFailed(errors.New("specify token when using enterprise url"))
 			}
 
 			return nil
 		},
+		After: func(ctx *cli.Context) error {
+			return writeSetupOutput(&actionsOpts.output, runOutput{
+				Command: ctx.Command.Name,
+				DryRun:  actionsOpts.dryRun,
+				Since:   *actionsOpts.since.Value(),
+				Until:   *actionsOpts.until.Value(),
+			})
+		},
 		Action: func(ctx *cli.Context) error {
+			if actionsOpts.printOptions {
+				return printOptions(actionsOpts)
+			}
+
+			start := time.Now()
+			summary, err := genCommandAction(ctx, actionsOpts)
 
-			return genCommandAction(ctx, actionsOpts)
+			outputErr := writeRunOutput(&actionsOpts.output, runOutput{
+				Command:         ctx.Command.Name,
+				DryRun:          actionsOpts.dryRun,
+				Since:           *actionsOpts.since.Value(),
+				Until:           *actionsOpts.until.Value(),
+				DurationSeconds: time.Since(start).Seconds(),
+			}, summary, ctx.Context.Err() != nil, err)
+			if outputErr != nil {
+				return outputErr
+			}
+
+			return err
 		},
 	}
 }
 
-func genCommandAction(ctx *cli.Context, opts *genActionOpts) error {
+func printOptions(opts *genActionOpts) error {
+	data, err := yaml.Marshal(opts.effectiveDefaults())
+	if err != nil {
+		return fmt.Errorf("failed to marshal options: %s", err.Error())
+	}
+
+	fmt.Print(string(data))
+	return nil
+}
+
+func genCommandAction(ctx *cli.Context, opts *genActionOpts) (*compose.Summary, error) {
+	if opts.username == "" {
+		return nil, fmt.Errorf("username is required - use the --username flag, the %s env variable or the user config", flagEnvVar("username"))
+	}
+
 	opts.Log.Info("generating report for the PKUP period", opts.Log.Args(
 		"since", opts.since.Value().Local().Format(logTimeFormat),
 		"until", opts.until.Value().Local().Format(logTimeFormat),
@@ -233,29 +444,64 @@ func genCommandAction(ctx *cli.Context, opts *genActionOpts) error {
 		var err error
 		opts.token, err = token.Get(opts.Log, opts.PkupClientID)
 		if err != nil {
-			return fmt.Errorf("failed to provide token: %s", err.Error())
+			return nil, fmt.Errorf("failed to provide token: %s", err.Error())
 		}
 	}
 
-	err := compose.New(ctx.Context, opts.Log).ForConfig(buildConfigFromOpts(opts), compose.Options{
-		Since: *opts.since.Value(),
-		Until: *opts.until.Value(),
-		Ci:    opts.ci,
-	})
+	composeOpts := compose.Options{
+		Since:         *opts.since.Value(),
+		Until:         *opts.until.Value(),
+		Ci:            opts.ci,
+		Parallelism:   opts.parallelism,
+		DryRun:        opts.dryRun,
+		FailurePolicy: opts.failurePolicy,
+	}
+	if opts.interactive && opts.dryRun {
+		return nil, fmt.Errorf("flags '--interactive' and '--dry-run' can't be used together")
+	}
+	if opts.interactive {
+		// spinners can't be displayed together with prompts
+		composeOpts.Ci = true
+		composeOpts.SelectCommits = view.SelectCommits
+	}
+
+	summary, err := compose.New(ctx.Context, opts.Log).ForConfig(buildConfigFromOpts(opts), composeOpts)
+	if summary != nil {
+		if printErr := printSummary(summary); printErr != nil {
+			return summary, printErr
+		}
+	}
 	if err != nil {
-		return err
+		return summary, err
+	}
+
+	if err := summaryExitError(summary); err != nil {
+		return summary, err
 	}
 
-	opts.Log.Info("all files saved to dir", opts.Log.Args("dir", opts.outputDir))
+	if !opts.dryRun {
+		opts.Log.Info("all files saved to dir", opts.Log.Args("dir", opts.outputDir))
+	}
 
-	fmt.Println(logo.ClaudeTip())
+	fmt.Fprintln(opts.output.humanOutput(), logo.ClaudeTip())
 
-	return nil
+	return summary, nil
 }
 
 func buildConfigFromOpts(opts *genActionOpts) *config.Config {
+	formats := opts.formats
+	if opts.templatePath == "" && len(formats) == 0 {
+		// generate .txt data file when there is no template
+		formats = []string{report.FormatTxt}
+	}
+
 	cfg := &config.Config{
-		Template: opts.templatePath,
+		Template:  opts.templatePath,
+		PDFLayout: opts.pdfLayoutPath,
+		Bots: config.Bots{
+			Mode:   opts.botsMode,
+			Logins: opts.botLogins,
+		},
 		Reports: []config.Report{
 			{
 				Signatures: []config.Signature{
@@ -266,6 +512,13 @@ func buildConfigFromOpts(opts *genActionOpts) *config.Config {
 				},
 				OutputDir:   opts.outputDir,
 				ExtraFields: opts.reportFields,
+				Formats:     formats,
+				GroupBy:     opts.groupBy,
+				Locales:     opts.locales,
+				Enrich:      opts.enrich,
+				Validation: config.Validation{
+					Mode: opts.validationMode,
+				},
 			},
 		},
 	}
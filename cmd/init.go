@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pPrecel/PKUP/internal/logo"
+	"github.com/pPrecel/PKUP/internal/view"
+	"github.com/pPrecel/PKUP/pkg/config"
+	"github.com/pPrecel/PKUP/pkg/github"
+	"github.com/urfave/cli/v2"
+)
+
+const initConfigHeader = `compose config generated by the 'pkup init' command
+validate it with 'pkup validate --config <path>' and generate reports with 'pkup compose --config <path>'
+all available fields are described by the 'pkup schema' command`
+
+func NewInitCommand(opts *Options) *cli.Command {
+	actionOpts := &initActionOpts{
+		Options: opts,
+		output:  ".pkupcompose.yaml",
+	}
+
+	return &cli.Command{
+		Name:      "init",
+		Usage:     "Asks for usernames, orgs, repos, template and output dir and writes a commented compose .yaml config",
+		UsageText: "pkup init --output .pkupcompose.yaml",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Usage:   "path to the generated config file",
+				Value:   actionOpts.output,
+				Aliases: []string{"o"},
+				Action: func(_ *cli.Context, path string) error {
+					actionOpts.output = path
+					return nil
+				},
+			},
+			&cli.BoolFlag{
+				Name:        "force",
+				Usage:       "overwrite the config file if exists",
+				Destination: &actionOpts.force,
+			},
+		},
+		Before: func(_ *cli.Context) error {
+			// print logo before any action
+			fmt.Printf("%s\n\n", logo.Build(opts.BuildVersion))
+
+			return nil
+		},
+		Action: func(ctx *cli.Context) error {
+			return initCommandAction(ctx, actionOpts)
+		},
+	}
+}
+
+func initCommandAction(ctx *cli.Context, opts *initActionOpts) error {
+	output, err := filepath.Abs(opts.output)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(output); err == nil && !opts.force {
+		return fmt.Errorf("config file '%s' already exists - use the --force flag to overwrite it", output)
+	}
+
+	cfg, err := view.InitConfig(view.InitWizardOptions{
+		ListOrgs: func(enterpriseURL, username string) ([]string, error) {
+			// public organizations are available without token
+			client, err := github.NewClient(ctx.Context, opts.Log, github.ClientOpts{
+				EnterpriseURL: enterpriseURL,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			return client.ListUserOrgs(username)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build config: %s", err.Error())
+	}
+
+	data, err := config.Marshal(cfg, initConfigHeader)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %s", err.Error())
+	}
+
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("failed to save config to '%s': %s", output, err.Error())
+	}
+
+	opts.Log.Info("config saved", opts.Log.Args("file", output))
+
+	// tokens can reference secrets that are not available yet
+	_, err = config.Read(output)
+	validationErr := &config.ValidationError{}
+	if errors.As(err, &validationErr) {
+		for _, issue := range validationErr.Issues {
+			opts.Log.Warn(issue.Message, opts.Log.Args(
+				"file", issue.File,
+				"line", issue.Line,
+				"column", issue.Column,
+				"field", issue.Field,
+			))
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to read saved config: %s", err.Error())
+	}
+
+	opts.Log.Info("generate reports with", opts.Log.Args("command", fmt.Sprintf("pkup compose --config %s", opts.output)))
+	return nil
+}
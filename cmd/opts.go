@@ -24,10 +24,15 @@ type Options struct {
 type composeActionOpts struct {
 	*Options
 
-	config string
-	since  cli.Timestamp
-	until  cli.Timestamp
-	ci     bool
+	config        string
+	state         string
+	since         cli.Timestamp
+	until         cli.Timestamp
+	parallelism   int
+	dryRun        bool
+	failurePolicy string
+	output        outputOpts
+	ci            bool
 }
 
 type sendActionOpts struct {
@@ -40,19 +45,66 @@ type sendActionOpts struct {
 type genActionOpts struct {
 	*Options
 
-	since         cli.Timestamp
-	until         cli.Timestamp
-	outputDir     string
-	token         string
-	username      string
-	enterpriseURL string
-	templatePath  string
-	orgs          []string
-	repos         []string
-	reportFields  map[string]string
-	uniqueOnly    bool
-	allBranches   bool
-	ci            bool
+	since          cli.Timestamp
+	until          cli.Timestamp
+	outputDir      string
+	token          string
+	username       string
+	enterpriseURL  string
+	templatePath   string
+	pdfLayoutPath  string
+	orgs           []string
+	repos          []string
+	formats        []string
+	groupBy        string
+	locales        []string
+	validationMode string
+	reportFields   map[string]string
+	enrich         bool
+	botsMode       string
+	botLogins      []string
+	uniqueOnly     bool
+	allBranches    bool
+	interactive    bool
+	parallelism    int
+	dryRun         bool
+	failurePolicy  string
+	output         outputOpts
+	userConfig     string
+	printOptions   bool
+	ci             bool
+}
+
+type renderActionOpts struct {
+	*Options
+
+	dir            string
+	templatePath   string
+	pdfLayoutPath  string
+	formats        []string
+	groupBy        string
+	locales        []string
+	validationMode string
+	reportFields   map[string]string
+}
+
+type validateActionOpts struct {
+	*Options
+
+	config string
+}
+
+type initActionOpts struct {
+	*Options
+
+	output string
+	force  bool
+}
+
+type schemaActionOpts struct {
+	*Options
+
+	output string
 }
 
 type versionActionOpts struct {
@@ -74,6 +126,18 @@ func (opts *genActionOpts) setDefaults() error {
 	return nil
 }
 
+func (opts *renderActionOpts) setDefaults() error {
+	if opts.dir == "" {
+		pwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get pwd error: %s", err.Error())
+		}
+		opts.dir = pwd
+	}
+
+	return nil
+}
+
 func parseReportFields(args []string) (map[string]string, error) {
 	reportFields := map[string]string{}
 	for _, field := range args {
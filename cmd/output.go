@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pPrecel/PKUP/internal/file"
+	"github.com/pPrecel/PKUP/pkg/compose"
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// human readable logs, spinners and the summary table
+	outputFormatText = "text"
+	// one JSON document printed when the command ends ( human output goes to the stderr )
+	outputFormatJSON = "json"
+)
+
+// list of all supported output formats
+var outputFormats = []string{
+	outputFormatText,
+	outputFormatJSON,
+}
+
+var outputFormatUsage = "format of the command output - one of: " + strings.Join(outputFormats, ", ")
+
+const outputFileUsage = "path to the file where the JSON output is saved instead of the stdout - used with '--output-format json'"
+
+const (
+	// composing for at least one user was stopped by the signal
+	runStatusInterrupted = "interrupted"
+)
+
+func validateOutputFormat(format string) error {
+	for _, f := range outputFormats {
+		if f == format {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported output format '%s' (supported: %s)", format, strings.Join(outputFormats, ", "))
+}
+
+// outputOpts configures the final document of the gen and compose commands
+type outputOpts struct {
+	format string
+	file   string
+	// writer for the JSON document ( os.Stdout if nil )
+	stdout io.Writer
+	// first error returned before the command action ( by the Before hook or flag actions )
+	setupErr error
+}
+
+// humanOutput returns writer for the logo and tips printed outside of the logger
+func (o *outputOpts) humanOutput() io.Writer {
+	if o.format == outputFormatJSON {
+		return os.Stderr
+	}
+
+	return os.Stdout
+}
+
+// setupFailed remembers the error to emit it in the JSON document from the After hook
+func (o *outputOpts) setupFailed(err error) error {
+	if err != nil && o.setupErr == nil {
+		o.setupErr = err
+	}
+
+	return err
+}
+
+// runOutput is the JSON document with results of the whole run
+type runOutput struct {
+	Command string `json:"command"`
+	// one of compose.UserStatus* values ( except skipped ) or interrupted
+	Status          string                `json:"status"`
+	DryRun          bool                  `json:"dryRun"`
+	Since           time.Time             `json:"since"`
+	Until           time.Time             `json:"until"`
+	DurationSeconds float64               `json:"durationSeconds"`
+	APICalls        int64                 `json:"apiCalls"`
+	Users           []compose.UserSummary `json:"users"`
+	Error           string                `json:"error,omitempty"`
+}
+
+// redirectHumanOutput moves the logo, logs, spinners and tables to the stderr
+// to keep the stdout clean for the JSON document
+func redirectHumanOutput(opts *outputOpts, log *pterm.Logger) {
+	if opts.format != outputFormatJSON {
+		return
+	}
+
+	log.Writer = os.Stderr
+	pterm.SetDefaultOutput(os.Stderr)
+	pterm.DefaultMultiPrinter.Writer = os.Stderr
+}
+
+// withSetupErrors remembers errors returned by flag actions
+// flag actions run after the Before hook so the command action is never called when any of them fails
+func withSetupErrors(opts *outputOpts, flags []cli.Flag) []cli.Flag {
+	for _, flag := range flags {
+		switch f := flag.(type) {
+		case *cli.StringFlag:
+			f.Action = recordSetupError(opts, f.Action)
+		case *cli.StringSliceFlag:
+			f.Action = recordSetupError(opts, f.Action)
+		case *cli.BoolFlag:
+			f.Action = recordSetupError(opts, f.Action)
+		case *cli.IntFlag:
+			f.Action = recordSetupError(opts, f.Action)
+		case *cli.TimestampFlag:
+			f.Action = recordSetupError(opts, f.Action)
+		}
+	}
+
+	return flags
+}
+
+func recordSetupError[T any](opts *outputOpts, action func(*cli.Context, T) error) func(*cli.Context, T) error {
+	if action == nil {
+		return nil
+	}
+
+	return func(ctx *cli.Context, value T) error {
+		return opts.setupFailed(action(ctx, value))
+	}
+}
+
+// writeSetupOutput writes the failure document when the command stopped before its action
+// ( the action writes the document on its own )
+func writeSetupOutput(opts *outputOpts, output runOutput) error {
+	if opts.setupErr == nil {
+		return nil
+	}
+
+	return writeRunOutput(opts, output, nil, false, opts.setupErr)
+}
+
+// writeRunOutput writes the JSON document to the output file or the stdout
+// does nothing for the text output format
+func writeRunOutput(opts *outputOpts, output runOutput, summary *compose.Summary, interrupted bool, runErr error) error {
+	if opts.format != outputFormatJSON {
+		return nil
+	}
+
+	output.Status = runStatus(summary, interrupted, runErr)
+	output.Users = []compose.UserSummary{}
+	if summary != nil {
+		output.APICalls = summary.APICalls
+		output.Users = summary.Users
+	}
+	if runErr != nil {
+		output.Error = runErr.Error()
+	}
+
+	// keep URLs from errors readable
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		return fmt.Errorf("failed to marshal output: %s", err.Error())
+	}
+	data := buf.Bytes()
+
+	if opts.file != "" {
+		path, err := filepath.Abs(opts.file)
+		if err != nil {
+			return err
+		}
+
+		if err := file.WriteAtomic(path, data); err != nil {
+			return fmt.Errorf("failed to save output to file '%s': %s", path, err.Error())
+		}
+
+		return nil
+	}
+
+	stdout := opts.stdout
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+
+	_, err := stdout.Write(data)
+	return err
+}
+
+func runStatus(summary *compose.Summary, interrupted bool, runErr error) string {
+	switch {
+	case interrupted:
+		return runStatusInterrupted
+	case summary == nil || summary.Failed():
+		return compose.UserStatusFailed
+	case summary.Partial():
+		return compose.UserStatusPartial
+	case runErr != nil:
+		return compose.UserStatusFailed
+	default:
+		return compose.UserStatusSucceeded
+	}
+}
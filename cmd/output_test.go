@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pPrecel/PKUP/pkg/compose"
+	"github.com/pterm/pterm"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func Test_runStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		summary     *compose.Summary
+		interrupted bool
+		runErr      error
+		want        string
+	}{
+		{
+			name: "success",
+			summary: &compose.Summary{Users: []compose.UserSummary{
+				{User: "pPrecel", Status: compose.UserStatusSucceeded},
+				{User: "kyma-bot", Status: compose.UserStatusSkipped},
+			}},
+			want: compose.UserStatusSucceeded,
+		},
+		{
+			name: "partial",
+			summary: &compose.Summary{Users: []compose.UserSummary{
+				{User: "pPrecel", Status: compose.UserStatusSucceeded},
+				{User: "kyma-bot", Status: compose.UserStatusFailed},
+			}},
+			runErr: errors.New("composing failed for some users or repos"),
+			want:   compose.UserStatusPartial,
+		},
+		{
+			name: "failed",
+			summary: &compose.Summary{Users: []compose.UserSummary{
+				{User: "pPrecel", Status: compose.UserStatusFailed},
+			}},
+			runErr: errors.New("composing failed for all users"),
+			want:   compose.UserStatusFailed,
+		},
+		{
+			name:   "failed without summary",
+			runErr: errors.New("failed to read config"),
+			want:   compose.UserStatusFailed,
+		},
+		{
+			name: "interrupted",
+			summary: &compose.Summary{Users: []compose.UserSummary{
+				{User: "pPrecel", Status: compose.UserStatusSucceeded},
+			}},
+			interrupted: true,
+			runErr:      errors.New("context canceled"),
+			want:        runStatusInterrupted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, runStatus(tt.summary, tt.interrupted, tt.runErr))
+		})
+	}
+}
+
+func Test_writeRunOutput(t *testing.T) {
+	summary := &compose.Summary{
+		Users: []compose.UserSummary{
+			{User: "pPrecel", Status: compose.UserStatusSucceeded, Commits: 3},
+		},
+		APICalls: 12,
+	}
+
+	t.Run("write only the JSON document to the stdout", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		err := writeRunOutput(&outputOpts{
+			format: outputFormatJSON,
+			stdout: stdout,
+		}, runOutput{Command: "gen"}, summary, false, nil)
+		require.NoError(t, err)
+
+		output := runOutput{}
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+		require.Equal(t, "gen", output.Command)
+		require.Equal(t, compose.UserStatusSucceeded, output.Status)
+		require.Equal(t, int64(12), output.APICalls)
+		require.Equal(t, summary.Users, output.Users)
+		require.Empty(t, output.Error)
+	})
+
+	t.Run("write the document to the output file", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		outputPath := filepath.Join(t.TempDir(), "output.json")
+		err := writeRunOutput(&outputOpts{
+			format: outputFormatJSON,
+			file:   outputPath,
+			stdout: stdout,
+		}, runOutput{Command: "compose"}, nil, false, errors.New("failed to read config"))
+		require.NoError(t, err)
+		require.Empty(t, stdout.String())
+
+		data, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+
+		output := runOutput{}
+		require.NoError(t, json.Unmarshal(data, &output))
+		require.Equal(t, "compose", output.Command)
+		require.Equal(t, compose.UserStatusFailed, output.Status)
+		require.Equal(t, []compose.UserSummary{}, output.Users)
+		require.Equal(t, "failed to read config", output.Error)
+	})
+
+	t.Run("do nothing for the text format", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		err := writeRunOutput(&outputOpts{
+			format: outputFormatText,
+			stdout: stdout,
+		}, runOutput{Command: "gen"}, summary, false, nil)
+		require.NoError(t, err)
+		require.Empty(t, stdout.String())
+	})
+}
+
+func Test_outputOpts_humanOutput(t *testing.T) {
+	require.Equal(t, os.Stdout, (&outputOpts{format: outputFormatText}).humanOutput())
+	require.Equal(t, os.Stderr, (&outputOpts{format: outputFormatJSON}).humanOutput())
+}
+
+func Test_setupFailureOutput(t *testing.T) {
+	tests := []struct {
+		name      string
+		command   *cli.Command
+		args      []string
+		wantError string
+	}{
+		{
+			name:      "gen with missing user config",
+			command:   NewGenCommand(fixOutputOptions()),
+			args:      []string{"--user-config", "/does/not/exist.yaml"},
+			wantError: "/does/not/exist.yaml",
+		},
+		{
+			name:      "gen with invalid flag",
+			command:   NewGenCommand(fixOutputOptions()),
+			args:      []string{"--parallelism", "0"},
+			wantError: "parallelism '0' must be greater than 0",
+		},
+		{
+			name:      "compose with invalid flag",
+			command:   NewComposeCommand(fixOutputOptions()),
+			args:      []string{"--failure-policy", "unknown"},
+			wantError: "unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "output.json")
+			app := &cli.App{
+				Name:     "pkup",
+				Commands: []*cli.Command{tt.command},
+			}
+
+			args := append([]string{"pkup", tt.command.Name,
+				"--output-format", outputFormatJSON,
+				"--output-file", outputPath,
+			}, tt.args...)
+			err := app.Run(args)
+			require.Error(t, err)
+
+			data, readErr := os.ReadFile(outputPath)
+			require.NoError(t, readErr)
+
+			output := runOutput{}
+			require.NoError(t, json.Unmarshal(data, &output))
+			require.Equal(t, tt.command.Name, output.Command)
+			require.Equal(t, compose.UserStatusFailed, output.Status)
+			require.Contains(t, output.Error, tt.wantError)
+		})
+	}
+}
+
+func fixOutputOptions() *Options {
+	return &Options{
+		Log: pterm.DefaultLogger.WithWriter(&bytes.Buffer{}),
+	}
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pPrecel/PKUP/internal/logo"
+	"github.com/pPrecel/PKUP/pkg/report"
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v2"
+)
+
+func NewRenderCommand(opts *Options) *cli.Command {
+	actionsOpts := &renderActionOpts{
+		Options: opts,
+	}
+
+	return &cli.Command{
+		Name:  "render",
+		Usage: "Renders reports again based on the " + report.ManifestFilename + " and artifacts from the output dir without calling GitHub",
+		UsageText: "pkup render \\\n" +
+			"\t--dir <output-dir> \\\n" +
+			"\t--template <path-to-template>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "dir",
+				Usage: "directory path with files generated by the gen or compose command ( removed .diff files are skipped in the report )",
+				Action: func(_ *cli.Context, dir string) error {
+					dir, err := filepath.Abs(filepath.Clean(dir))
+					if err != nil {
+						return err
+					}
+
+					actionsOpts.dir = dir
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:    "template",
+				Usage:   "full path to the template (.docx, .odt or go template .tmpl/.txt/.md) - overrides the template used before",
+				Aliases: []string{"tmpl"},
+				Action: func(_ *cli.Context, path string) error {
+					path, err := filepath.Abs(filepath.Clean(path))
+					if err != nil {
+						return err
+					}
+
+					actionsOpts.templatePath = path
+					return nil
+				},
+			},
+			&cli.StringSliceFlag{
+				Name:  "format",
+				Usage: "additional report format - one of: " + strings.Join(report.Formats, ", ") + " - overrides formats used before",
+				Action: func(_ *cli.Context, formats []string) error {
+					for _, format := range formats {
+						if err := report.ValidateFormat(format); err != nil {
+							return err
+						}
+					}
+
+					actionsOpts.formats = formats
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:  "group-by",
+				Usage: "split report results into sections - one of: " + strings.Join(report.GroupByOptions, ", "),
+				Action: func(_ *cli.Context, groupBy string) error {
+					if err := report.ValidateGroupBy(groupBy); err != nil {
+						return err
+					}
+
+					actionsOpts.groupBy = groupBy
+					return nil
+				},
+			},
+			&cli.StringSliceFlag{
+				Name:  "locale",
+				Usage: "report language - one of: " + strings.Join(report.Locales, ", ") + " ( every language is saved to separate files when used many times )",
+				Action: func(_ *cli.Context, locales []string) error {
+					for _, locale := range locales {
+						if err := report.ValidateLocale(locale); err != nil {
+							return err
+						}
+					}
+
+					actionsOpts.locales = locales
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:  "pdf-layout",
+				Usage: "full path to the yaml file with the pdf report layout - used with '--format pdf'",
+				Action: func(_ *cli.Context, path string) error {
+					path, err := filepath.Abs(filepath.Clean(path))
+					if err != nil {
+						return err
+					}
+
+					actionsOpts.pdfLayoutPath = path
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:  "validation",
+				Usage: "what to do when the rendered report contains unreplaced placeholders or unused report fields - one of: " + strings.Join(report.ValidationModes, ", "),
+				Action: func(_ *cli.Context, mode string) error {
+					if err := report.ValidateValidationMode(mode); err != nil {
+						return err
+					}
+
+					actionsOpts.validationMode = mode
+					return nil
+				},
+			},
+			&cli.StringSliceFlag{
+				Name:  "report-field",
+				Usage: "custom field that will be replace in the output report - in format FIELD=VALUE - overrides fields used before",
+				Action: func(_ *cli.Context, fields []string) error {
+					reportFields, err := parseReportFields(fields)
+					actionsOpts.reportFields = reportFields
+					return err
+				},
+			},
+			&cli.BoolFlag{
+				Name:               "v",
+				Usage:              "verbose log mode",
+				DisableDefaultText: true,
+				Category:           loggingCategory,
+				Action: func(_ *cli.Context, _ bool) error {
+					opts.Log.Level = pterm.LogLevelDebug
+					return nil
+				},
+			},
+			&cli.BoolFlag{
+				Name:               "vv",
+				Usage:              "trace log mode",
+				DisableDefaultText: true,
+				Category:           loggingCategory,
+				Action: func(_ *cli.Context, _ bool) error {
+					opts.Log.Level = pterm.LogLevelTrace
+					return nil
+				},
+			},
+		},
+		Before: func(_ *cli.Context) error {
+			// print logo before any action
+			fmt.Printf("%s\n\n", logo.Build(opts.BuildVersion))
+
+			return nil
+		},
+		Action: func(_ *cli.Context) error {
+			// default
+			if err := actionsOpts.setDefaults(); err != nil {
+				return err
+			}
+
+			return renderCommandAction(actionsOpts)
+		},
+	}
+}
+
+func renderCommandAction(opts *renderActionOpts) error {
+	reportOpts, err := report.ReadManifest(opts.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read reports data from dir '%s': %s", opts.dir, err.Error())
+	}
+
+	applyRenderOverrides(reportOpts, opts)
+
+	opts.Log.Info("rendering report for the PKUP period", opts.Log.Args(
+		"dir", opts.dir,
+		"since", reportOpts.PeriodFrom.Local().Format(logTimeFormat),
+		"until", reportOpts.PeriodTill.Local().Format(logTimeFormat),
+	))
+
+	output, err := report.Render(*reportOpts)
+	if err != nil {
+		return fmt.Errorf("failed to render report: %s", err.Error())
+	}
+
+	for _, warning := range output.Warnings {
+		opts.Log.Warn("report validation", opts.Log.Args("issue", warning))
+	}
+
+	for _, file := range output.Files {
+		opts.Log.Info("report saved", opts.Log.Args("file", file))
+	}
+
+	return nil
+}
+
+// applyRenderOverrides replaces options saved in the manifest with values from flags
+func applyRenderOverrides(reportOpts *report.Options, opts *renderActionOpts) {
+	if opts.templatePath != "" {
+		reportOpts.TemplatePath = opts.templatePath
+	}
+
+	if len(opts.formats) > 0 {
+		reportOpts.Formats = opts.formats
+	}
+
+	if opts.groupBy != "" {
+		reportOpts.GroupBy = opts.groupBy
+	}
+
+	if len(opts.locales) > 0 {
+		reportOpts.Locales = opts.locales
+	}
+
+	if opts.pdfLayoutPath != "" {
+		reportOpts.PDFLayoutPath = opts.pdfLayoutPath
+	}
+
+	if opts.validationMode != "" {
+		reportOpts.Validation.Mode = opts.validationMode
+	}
+
+	if len(opts.reportFields) > 0 {
+		reportOpts.CustomValues = opts.reportFields
+	}
+}
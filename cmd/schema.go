@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pPrecel/PKUP/pkg/config"
+	"github.com/urfave/cli/v2"
+)
+
+func NewSchemaCommand(opts *Options) *cli.Command {
+	actionOpts := &schemaActionOpts{
+		Options: opts,
+	}
+
+	return &cli.Command{
+		Name:  "schema",
+		Usage: "Prints JSON Schema of the compose .yaml config file used by editors to validate and complete the config",
+		UsageText: "pkup schema > pkup-schema.json\n" +
+			"pkup schema --output pkup-schema.json",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Usage:   "path to the file where schema will be saved ( default: print to stdout )",
+				Aliases: []string{"o"},
+				Action: func(_ *cli.Context, path string) error {
+					path, err := filepath.Abs(path)
+					if err != nil {
+						return err
+					}
+
+					actionOpts.output = path
+					return nil
+				},
+			},
+		},
+		Action: func(_ *cli.Context) error {
+			return schemaCommandAction(actionOpts)
+		},
+	}
+}
+
+func schemaCommandAction(opts *schemaActionOpts) error {
+	schema, err := config.GenerateSchema()
+	if err != nil {
+		return fmt.Errorf("failed to generate schema: %s", err.Error())
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(schema); err != nil {
+		return fmt.Errorf("failed to marshal schema: %s", err.Error())
+	}
+
+	if opts.output == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+
+	if err := os.WriteFile(opts.output, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to save schema to '%s': %s", opts.output, err.Error())
+	}
+
+	opts.Log.Info("schema saved", opts.Log.Args("file", opts.output))
+	return nil
+}
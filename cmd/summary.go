@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pPrecel/PKUP/pkg/compose"
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// composing failed for all users
+	exitCodeTotalFailure = 2
+	// composing failed for some users or repos
+	exitCodePartialFailure = 3
+)
+
+var failurePolicyUsage = "what to do when composing fails for the repo - one of: " + strings.Join(compose.FailurePolicies, ", ") + " ( default: " + compose.FailurePolicySkipUser + " )"
+
+// printSummary prints the table with the status of every user
+func printSummary(summary *compose.Summary) error {
+	data := pterm.TableData{
+		{"User", "Status", "Commits", "Reason"},
+	}
+	for _, user := range summary.Users {
+		data = append(data, []string{
+			user.User,
+			user.Status,
+			strconv.Itoa(user.Commits),
+			strings.Join(user.Errors, "; "),
+		})
+	}
+
+	return pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+}
+
+// summaryExitError returns error with the exit code based on statuses of users
+func summaryExitError(summary *compose.Summary) error {
+	switch {
+	case summary.Failed():
+		return cli.Exit("composing failed for all users", exitCodeTotalFailure)
+	case summary.Partial():
+		return cli.Exit("composing failed for some users or repos", exitCodePartialFailure)
+	default:
+		return nil
+	}
+}
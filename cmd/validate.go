@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/pPrecel/PKUP/internal/logo"
+	"github.com/pPrecel/PKUP/pkg/config"
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v2"
+)
+
+func NewValidateCommand(opts *Options) *cli.Command {
+	actionOpts := &validateActionOpts{
+		Options: opts,
+	}
+
+	return &cli.Command{
+		Name:      "validate",
+		Usage:     "Validates the compose .yaml config file and prints all found issues with their positions",
+		UsageText: "pkup validate --config .pkupcompose.yaml",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "config",
+				Value:       ".pkupcompose.yaml",
+				Destination: &actionOpts.config,
+				Action: func(_ *cli.Context, path string) error {
+					path, err := filepath.Abs(path)
+					if err != nil {
+						return err
+					}
+
+					actionOpts.config = path
+					return nil
+				},
+			},
+			&cli.BoolFlag{
+				Name:               "v",
+				Usage:              "verbose log mode",
+				DisableDefaultText: true,
+				Category:           loggingCategory,
+				Action: func(_ *cli.Context, _ bool) error {
+					opts.Log.Level = pterm.LogLevelDebug
+					return nil
+				},
+			},
+		},
+		Before: func(_ *cli.Context) error {
+			// print logo before any action
+			fmt.Printf("%s\n\n", logo.Build(opts.BuildVersion))
+
+			return nil
+		},
+		Action: func(_ *cli.Context) error {
+			return validateCommandAction(actionOpts)
+		},
+	}
+}
+
+func validateCommandAction(opts *validateActionOpts) error {
+	opts.Log.Debug("validating config", opts.Log.Args(
+		"config", opts.config,
+	))
+
+	_, err := config.Read(opts.config)
+	validationErr := &config.ValidationError{}
+	if errors.As(err, &validationErr) {
+		for _, issue := range validationErr.Issues {
+			opts.Log.Error(issue.Message, opts.Log.Args(
+				"file", issue.File,
+				"line", issue.Line,
+				"column", issue.Column,
+				"field", issue.Field,
+			))
+		}
+
+		return fmt.Errorf("config '%s' is invalid: found %d issues", opts.config, len(validationErr.Issues))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config from path '%s': %s", opts.config, err.Error())
+	}
+
+	opts.Log.Info("config is valid", opts.Log.Args(
+		"config", opts.config,
+	))
+
+	return nil
+}
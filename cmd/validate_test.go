@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pterm/pterm"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateCommandAction(t *testing.T) {
+	t.Run("log file of issues from included files", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		includedPath := filepath.Join(tmpDir, "shared", "orgs.yaml")
+		require.NoError(t, os.MkdirAll(filepath.Dir(includedPath), os.ModePerm))
+		require.NoError(t, os.WriteFile(includedPath, []byte("orgs:\n- name: kyma-project/cli\n"), 0644))
+		configPath := filepath.Join(tmpDir, "config.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte("include: [shared/orgs.yaml]\n"+
+			"reports:\n- signatures:\n  - username: pPrecel\n"), 0644))
+
+		buf := &bytes.Buffer{}
+		err := validateCommandAction(&validateActionOpts{
+			Options: &Options{
+				Log: pterm.DefaultLogger.WithWriter(buf).WithFormatter(pterm.LogFormatterJSON),
+			},
+			config: configPath,
+		})
+		require.ErrorContains(t, err, "found 1 issues")
+		require.Contains(t, buf.String(), `"file":"`+includedPath+`"`)
+		require.Contains(t, buf.String(), `"field":"orgs[0].name"`)
+	})
+}
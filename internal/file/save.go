@@ -3,20 +3,41 @@ package file
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 )
 
 func BuildDiffFilename(sha, org, repo string) string {
 	return fmt.Sprintf("%s_%s_%s.diff", org, repo, cutSHA(sha))
 }
 
+// Create writes content to the temporary file and renames it
+// the file is never left half-written when the program is interrupted
 func Create(dir, filename, content string) error {
-	file, err := os.Create(fmt.Sprintf("%s/%s", dir, filename))
+	return WriteAtomic(filepath.Join(dir, filename), []byte(content))
+}
+
+// WriteAtomic writes data to the temporary file in the same dir and renames it to the path
+func WriteAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
 	if err != nil {
 		return err
 	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
 
-	_, err = file.WriteString(content)
-	return err
+	return os.Rename(tmp.Name(), path)
 }
 
 func cutSHA(fullSHA string) string {
@@ -0,0 +1,29 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreate(t *testing.T) {
+	t.Run("replace file without leaving temporary files", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, Create(dir, "test.diff", "old"))
+		require.NoError(t, Create(dir, "test.diff", "new"))
+
+		data, err := os.ReadFile(filepath.Join(dir, "test.diff"))
+		require.NoError(t, err)
+		require.Equal(t, "new", string(data))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("missing dir", func(t *testing.T) {
+		require.Error(t, Create(filepath.Join(t.TempDir(), "missing"), "test.diff", "content"))
+	})
+}
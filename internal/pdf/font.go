@@ -0,0 +1,145 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// glyphs missing in the WinAnsiEncoding (mostly Polish letters) replace rarely used codes
+var extraGlyphs = []struct {
+	r     rune
+	code  byte
+	glyph string
+}{
+	{'ą', 0x81, "aogonek"},
+	{'Ą', 0x8D, "Aogonek"},
+	{'ć', 0x8F, "cacute"},
+	{'Ć', 0x90, "Cacute"},
+	{'ę', 0x9D, "eogonek"},
+	{'Ę', 0xA4, "Eogonek"},
+	{'ł', 0xA6, "lslash"},
+	{'Ł', 0xA8, "Lslash"},
+	{'ń', 0xAF, "nacute"},
+	{'Ń', 0xB2, "Nacute"},
+	{'ś', 0xB3, "sacute"},
+	{'Ś', 0xB4, "Sacute"},
+	{'ź', 0xB8, "zacute"},
+	{'Ź', 0xB9, "Zacute"},
+	{'ż', 0xBC, "zdotaccent"},
+	{'Ż', 0xBD, "Zdotaccent"},
+}
+
+// WinAnsiEncoding codes outside of the Latin-1 range
+var winAnsiGlyphs = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+// Helvetica glyph widths for the printable ASCII characters (1/1000 of the font size)
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // ' ' - '/'
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // '0' - '?'
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // '@' - 'O'
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // 'P' - '_'
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // '`' - 'o'
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // 'p' - '~'
+}
+
+func differences() string {
+	entries := []string{}
+	for _, g := range extraGlyphs {
+		entries = append(entries, fmt.Sprintf("%d /%s", g.code, g.glyph))
+	}
+
+	return strings.Join(entries, " ")
+}
+
+// encode converts text to the single-byte document encoding
+// unsupported characters are replaced with '?'
+func encode(text string) string {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		out = append(out, encodeRune(r))
+	}
+
+	return string(out)
+}
+
+func encodeRune(r rune) byte {
+	for _, g := range extraGlyphs {
+		if g.r == r {
+			return g.code
+		}
+	}
+
+	if code, ok := winAnsiGlyphs[r]; ok {
+		return code
+	}
+
+	if r >= 0x20 && r <= 0x7E || r >= 0xA0 && r <= 0xFF && !isOverriddenCode(byte(r)) {
+		return byte(r)
+	}
+
+	return '?'
+}
+
+func isOverriddenCode(code byte) bool {
+	for _, g := range extraGlyphs {
+		if g.code == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TextWidth returns approximated width of the text in points
+func TextWidth(text string, size float64, bold bool) float64 {
+	width := 0
+	for _, r := range text {
+		if r >= ' ' && r <= '~' {
+			width += helveticaWidths[r-' ']
+		} else {
+			width += 556
+		}
+	}
+
+	w := float64(width) * size / 1000
+	if bold {
+		// bold glyphs are slightly wider
+		w *= 1.07
+	}
+
+	return w
+}
+
+// WrapText splits text into lines that fit in the given width
+func WrapText(text string, width, size float64, bold bool) []string {
+	lines := []string{}
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := strings.TrimSpace(line + " " + word)
+			if line != "" && TextWidth(candidate, size, bold) > width {
+				lines = append(lines, line)
+				candidate = word
+			}
+
+			// break words longer than the whole line
+			for TextWidth(candidate, size, bold) > width && len([]rune(candidate)) > 1 {
+				runes := []rune(candidate)
+				i := len(runes) - 1
+				for i > 1 && TextWidth(string(runes[:i]), size, bold) > width {
+					i--
+				}
+				lines = append(lines, string(runes[:i]))
+				candidate = string(runes[i:])
+			}
+
+			line = candidate
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
@@ -0,0 +1,61 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument_Write(t *testing.T) {
+	t.Run("write valid xref table", func(t *testing.T) {
+		doc := New()
+		doc.AddPage()
+		doc.Text(50, 50, 10, true, "Filip Strózik (test)")
+		doc.AddPage()
+		doc.Line(50, 50, 100, 50)
+
+		buf := bytes.NewBuffer(nil)
+		require.NoError(t, doc.Write(buf))
+
+		out := buf.String()
+		require.True(t, strings.HasPrefix(out, "%PDF-1.4\n"))
+		require.True(t, strings.HasSuffix(out, "%%EOF\n"))
+		require.Contains(t, out, `(Filip Str`+"\xf3"+`zik \(test\)) Tj`)
+		require.Contains(t, out, "/Count 2")
+
+		startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(out)
+		require.Len(t, startxref, 2)
+		xrefOffset, err := strconv.Atoi(startxref[1])
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(out[xrefOffset:], "xref\n"))
+
+		entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(out[xrefOffset:], -1)
+		require.Len(t, entries, 10)
+		for i, entry := range entries {
+			offset, err := strconv.Atoi(entry[1])
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(out[offset:], fmt.Sprintf("%d 0 obj\n", i+1)))
+		}
+	})
+}
+
+func TestWrapText(t *testing.T) {
+	t.Run("wrap words", func(t *testing.T) {
+		lines := WrapText("aaaa bbbb cccc", TextWidth("aaaa bbbb", 10, false), 10, false)
+		require.Equal(t, []string{"aaaa bbbb", "cccc"}, lines)
+	})
+
+	t.Run("break long words", func(t *testing.T) {
+		lines := WrapText("aaaaaaaa", TextWidth("aaaa", 10, false), 10, false)
+		require.Equal(t, []string{"aaaa", "aaaa"}, lines)
+	})
+
+	t.Run("encode polish letters", func(t *testing.T) {
+		require.Equal(t, "\x81\xa6\xbc?", encode("ąłż木"))
+	})
+}
@@ -82,17 +82,19 @@ func selectChannelsForSpinners(workingSpinners map[string]*pterm.SpinnerPrinter,
 		if ok {
 			workingSpinners[taskName].Fail(err)
 		}
-	case commits, ok := <-channels.valuesChan:
+	case repoCommits, ok := <-channels.valuesChan:
 		if ok {
+			commits, excludedSummary := splitExcluded(repoCommits)
 			if len(commits) == 0 {
 				workingSpinners[taskName].Warning(
-					fmt.Sprintf("skipping %s no user activity detected", taskName),
+					withSummary(fmt.Sprintf("skipping %s no user activity detected", taskName), excludedSummary),
 				)
 			} else {
+				additions, deletions := sumLines(commits)
 				text := buildTreeString(
-					fmt.Sprintf(
-						"found %d commits for %s",
-						len(commits), taskName),
+					withSummary(fmt.Sprintf(
+						"found %d commits for %s (+%d/-%d lines)",
+						len(commits), taskName, additions, deletions), excludedSummary),
 					commitsToStringList(commits),
 				)
 				workingSpinners[taskName].Success(text)
@@ -141,7 +143,7 @@ func startSpinnersWithPrinter(tasks map[string]taskChannels, multi *pterm.MultiP
 func commitsToStringList(commits []*RepoCommit) []string {
 	stringList := []string{}
 	for _, commit := range commits {
-		stringList = append(stringList, fmt.Sprintf("%s/%s - %s", commit.Org, commit.Repo, commit.Message))
+		stringList = append(stringList, fmt.Sprintf("%s/%s - %s (+%d/-%d)", commit.Org, commit.Repo, commit.Message, commit.Additions, commit.Deletions))
 	}
 
 	return stringList
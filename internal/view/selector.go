@@ -0,0 +1,113 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+
+	go_github "github.com/google/go-github/v53/github"
+	"github.com/pPrecel/PKUP/pkg/report"
+	"github.com/pterm/pterm"
+)
+
+// SelectCommits asks user which commits should be part of the report and allows editing their descriptions
+// commits are grouped by repository and all of them are selected by default
+func SelectCommits(results []report.Result) ([]report.Result, error) {
+	options := []string{}
+	for _, result := range results {
+		for _, commit := range result.CommitList.Commits {
+			options = append(options, buildCommitOption(result, commit))
+		}
+	}
+
+	if len(options) == 0 {
+		return results, nil
+	}
+
+	selectedOptions, err := pterm.DefaultInteractiveMultiselect.
+		WithOptions(options).
+		WithDefaultOptions(options).
+		WithMaxHeight(15).
+		Show("Select commits for the report ( enter - toggle, tab - confirm )")
+	if err != nil {
+		return nil, err
+	}
+
+	selected := map[string]struct{}{}
+	for _, option := range selectedOptions {
+		selected[option] = struct{}{}
+	}
+
+	edit, err := pterm.DefaultInteractiveConfirm.
+		WithDefaultValue(false).
+		Show("Edit descriptions of selected commits?")
+	if err != nil {
+		return nil, err
+	}
+
+	selectedResults := []report.Result{}
+	for _, result := range results {
+		commits := []*go_github.RepositoryCommit{}
+		for _, commit := range result.CommitList.Commits {
+			option := buildCommitOption(result, commit)
+			if _, ok := selected[option]; !ok {
+				continue
+			}
+
+			if edit {
+				commit, err = editCommitDescription(option, commit)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			commits = append(commits, commit)
+		}
+
+		result.CommitList.Commits = commits
+		selectedResults = append(selectedResults, result)
+	}
+
+	return selectedResults, nil
+}
+
+func buildCommitOption(result report.Result, commit *go_github.RepositoryCommit) string {
+	sha := commit.GetSHA()
+	if len(sha) > 8 {
+		sha = sha[:8]
+	}
+
+	return fmt.Sprintf("%s/%s | %s | %s",
+		result.Org, result.Repo, sha, strings.Split(commit.GetCommit().GetMessage(), "\n")[0])
+}
+
+// editCommitDescription returns copy of the commit with the replaced first line of the message
+// the commit is returned without changes when the description is empty
+func editCommitDescription(option string, commit *go_github.RepositoryCommit) (*go_github.RepositoryCommit, error) {
+	description, err := pterm.DefaultInteractiveTextInput.
+		Show(fmt.Sprintf("%s\nNew description ( leave empty to keep )", option))
+	if err != nil {
+		return nil, err
+	}
+
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return commit, nil
+	}
+
+	// copy commit to not modify commits shared between users
+	commitCopy := *commit
+	innerCommit := go_github.Commit{}
+	if commit.Commit != nil {
+		innerCommit = *commit.Commit
+	}
+
+	_, body, _ := strings.Cut(innerCommit.GetMessage(), "\n")
+	message := description
+	if body != "" {
+		message += "\n" + body
+	}
+
+	innerCommit.Message = &message
+	commitCopy.Commit = &innerCommit
+	return &commitCopy, nil
+}
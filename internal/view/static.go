@@ -3,7 +3,6 @@ package view
 import (
 	"fmt"
 	"io"
-	"os"
 
 	"github.com/pterm/pterm"
 )
@@ -14,7 +13,7 @@ type staticView struct {
 }
 
 func (sv *staticView) NewWriter() io.Writer {
-	return os.Stdout
+	return sv.log.Writer
 }
 
 func newStatic(log *pterm.Logger) MultiTaskView {
@@ -53,14 +52,16 @@ func selectChannelsForLogger(log *pterm.Logger, taskName string, channels taskCh
 		if ok {
 			log.Error(err.Error())
 		}
-	case repoCommits, ok := <-channels.valuesChan:
+	case allCommits, ok := <-channels.valuesChan:
 		if ok {
+			repoCommits, excludedSummary := splitExcluded(allCommits)
 			if len(repoCommits) == 0 {
 				log.Warn(
-					fmt.Sprintf("skipping %s no user activity detected", taskName),
+					withSummary(fmt.Sprintf("skipping %s no user activity detected", taskName), excludedSummary),
 				)
 			} else {
-				text := fmt.Sprintf("found %d commits for %s", len(repoCommits), taskName)
+				additions, deletions := sumLines(repoCommits)
+				text := withSummary(fmt.Sprintf("found %d commits for %s (+%d/-%d lines)", len(repoCommits), taskName, additions, deletions), excludedSummary)
 				args := []pterm.LoggerArgument{}
 				for _, commit := range repoCommits {
 					args = append(args, pterm.LoggerArgument{
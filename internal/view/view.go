@@ -1,7 +1,9 @@
 package view
 
 import (
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/pterm/pterm"
 )
@@ -13,10 +15,61 @@ type MultiTaskView interface {
 }
 
 type RepoCommit struct {
-	Org     string
-	Repo    string
-	Message string
-	SHA     string
+	Org       string
+	Repo      string
+	Message   string
+	SHA       string
+	Additions int
+	Deletions int
+	// name of the rule that excluded the commit from the report ( empty when included )
+	ExcludedBy string
+}
+
+func sumLines(commits []*RepoCommit) (int, int) {
+	additions, deletions := 0, 0
+	for _, commit := range commits {
+		additions += commit.Additions
+		deletions += commit.Deletions
+	}
+
+	return additions, deletions
+}
+
+// splitExcluded returns commits included in the report and the summary of excluded ones ( e.g. "excluded 3: merges: 2, bots: 1" )
+func splitExcluded(commits []*RepoCommit) ([]*RepoCommit, string) {
+	included := []*RepoCommit{}
+	counts := map[string]int{}
+	rules := []string{}
+	for _, commit := range commits {
+		if commit.ExcludedBy == "" {
+			included = append(included, commit)
+			continue
+		}
+
+		if _, ok := counts[commit.ExcludedBy]; !ok {
+			rules = append(rules, commit.ExcludedBy)
+		}
+		counts[commit.ExcludedBy]++
+	}
+
+	if len(rules) == 0 {
+		return included, ""
+	}
+
+	ruleCounts := []string{}
+	for _, rule := range rules {
+		ruleCounts = append(ruleCounts, fmt.Sprintf("%s: %d", rule, counts[rule]))
+	}
+
+	return included, fmt.Sprintf("excluded %d: %s", len(commits)-len(included), strings.Join(ruleCounts, ", "))
+}
+
+func withSummary(text, summary string) string {
+	if summary == "" {
+		return text
+	}
+
+	return fmt.Sprintf("%s, %s", text, summary)
 }
 
 type taskChannels struct {
@@ -0,0 +1,323 @@
+package view
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pPrecel/PKUP/pkg/config"
+	"github.com/pPrecel/PKUP/pkg/report"
+	"github.com/pterm/pterm"
+)
+
+var (
+	// e.g.: "pPrecel"
+	usernameRegex = regexp.MustCompile(`^[\w-]+$`)
+	// e.g.: "kyma-project"
+	orgRegex = regexp.MustCompile(`^[\w.-]+$`)
+	// e.g.: "kyma-project/cli"
+	repoRegex = regexp.MustCompile(`^[\w.-]+/[\w.-]+$`)
+)
+
+const defaultToken = "${GITHUB_TOKEN}"
+
+type InitWizardOptions struct {
+	// returns organizations of the user from the GitHub instance ( empty url for opensource GitHub )
+	ListOrgs func(enterpriseURL, username string) ([]string, error)
+}
+
+// GitHub instance with the user's username
+type instance struct {
+	enterpriseURL string
+	username      string
+}
+
+func (i instance) String() string {
+	if i.enterpriseURL == "" {
+		return "github.com"
+	}
+
+	return i.enterpriseURL
+}
+
+// InitConfig asks user for report details and returns the compose config built from answers
+// every answer is validated and asked again when it's wrong
+func InitConfig(opts InitWizardOptions) (*config.Config, error) {
+	username, err := askText("GitHub username", "", validateUsername)
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := askInstances(username)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &config.Config{}
+	for len(cfg.Orgs) == 0 && len(cfg.Repos) == 0 {
+		for _, inst := range instances {
+			orgs, repos, err := askSources(inst, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			cfg.Orgs = append(cfg.Orgs, orgs...)
+			cfg.Repos = append(cfg.Repos, repos...)
+		}
+
+		if len(cfg.Orgs) == 0 && len(cfg.Repos) == 0 {
+			pterm.Warning.Println("at least one org or repo is required")
+		}
+	}
+
+	template, err := askText("Report template path ( .docx, .odt or go template .tmpl/.txt/.md - leave empty to generate the .txt report )", "", validateTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	outputDir, err := askText("Output dir", "reports/"+username, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	userReport := config.Report{
+		OutputDir: outputDir,
+	}
+	for _, inst := range instances {
+		userReport.Signatures = append(userReport.Signatures, config.Signature{
+			Username:      inst.username,
+			EnterpriseUrl: inst.enterpriseURL,
+		})
+	}
+
+	if template == "" {
+		userReport.Formats = []string{report.FormatTxt}
+	}
+
+	cfg.Template = template
+	cfg.Reports = []config.Report{userReport}
+	return cfg, nil
+}
+
+func askInstances(username string) ([]instance, error) {
+	instances := []instance{{username: username}}
+	for {
+		addEnterprise, err := pterm.DefaultInteractiveConfirm.
+			WithDefaultValue(false).
+			Show("Add enterprise GitHub instance?")
+		if err != nil || !addEnterprise {
+			return instances, err
+		}
+
+		enterpriseURL, err := askText("Enterprise GitHub API address ( e.g. https://github.my-corp/api/v3 )", "", validateURL)
+		if err != nil {
+			return nil, err
+		}
+
+		enterpriseUsername, err := askText(fmt.Sprintf("Username on %s", enterpriseURL), username, validateUsername)
+		if err != nil {
+			return nil, err
+		}
+
+		instances = append(instances, instance{
+			enterpriseURL: enterpriseURL,
+			username:      enterpriseUsername,
+		})
+	}
+}
+
+// askSources returns orgs and repos selected for the GitHub instance
+func askSources(inst instance, opts InitWizardOptions) ([]config.Org, []config.Remote, error) {
+	token, err := askText(fmt.Sprintf("Token for %s ( supports ${ENV}, env:<NAME>, file:<PATH>, keyring:<SERVICE>/<USER> and cmd:<COMMAND> )", inst), defaultToken, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	orgNames, err := askOrgs(inst, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repoNames, err := askList(fmt.Sprintf("Repos from %s ( comma separated <ORG>/<REPO> )", inst), validateRepo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	orgs := []config.Org{}
+	for _, name := range orgNames {
+		orgs = append(orgs, config.Org{
+			Remote: config.Remote{
+				Name:          name,
+				Token:         token,
+				EnterpriseUrl: inst.enterpriseURL,
+			},
+		})
+	}
+
+	repos := []config.Remote{}
+	for _, name := range repoNames {
+		repos = append(repos, config.Remote{
+			Name:          name,
+			Token:         token,
+			EnterpriseUrl: inst.enterpriseURL,
+		})
+	}
+
+	return orgs, repos, nil
+}
+
+// askOrgs allows to select organizations of the user listed from the API and to add other ones
+func askOrgs(inst instance, opts InitWizardOptions) ([]string, error) {
+	orgs := []string{}
+	if opts.ListOrgs != nil {
+		list, err := pterm.DefaultInteractiveConfirm.
+			WithDefaultValue(true).
+			Show(fmt.Sprintf("List public organizations of %s from %s?", inst.username, inst))
+		if err != nil {
+			return nil, err
+		}
+
+		if list {
+			orgs, err = selectUserOrgs(inst, opts)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	otherOrgs, err := askList(fmt.Sprintf("Other orgs from %s ( comma separated <ORG> )", inst), validateOrg)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(orgs, otherOrgs...), nil
+}
+
+func selectUserOrgs(inst instance, opts InitWizardOptions) ([]string, error) {
+	userOrgs, err := opts.ListOrgs(inst.enterpriseURL, inst.username)
+	if err != nil {
+		// orgs can be still typed by hand
+		pterm.Warning.Printfln("failed to list organizations: %s", err.Error())
+		return []string{}, nil
+	}
+
+	if len(userOrgs) == 0 {
+		pterm.Info.Printfln("no public organizations found for %s", inst.username)
+		return []string{}, nil
+	}
+
+	return pterm.DefaultInteractiveMultiselect.
+		WithOptions(userOrgs).
+		WithMaxHeight(15).
+		Show("Select organizations ( enter - toggle, tab - confirm )")
+}
+
+// askText asks until the answer is valid
+// returns the defaultValue when answer is empty
+func askText(prompt, defaultValue string, validate func(string) error) (string, error) {
+	if defaultValue != "" {
+		prompt = fmt.Sprintf("%s [%s]", prompt, defaultValue)
+	}
+
+	for {
+		answer, err := pterm.DefaultInteractiveTextInput.Show(prompt)
+		if err != nil {
+			return "", err
+		}
+
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			answer = defaultValue
+		}
+
+		if validate == nil {
+			return answer, nil
+		}
+
+		if err := validate(answer); err != nil {
+			pterm.Warning.Println(err.Error())
+			continue
+		}
+
+		return answer, nil
+	}
+}
+
+// askList asks for comma separated values until all of them are valid
+func askList(prompt string, validate func(string) error) ([]string, error) {
+	answer, err := askText(prompt, "", func(answer string) error {
+		for _, value := range splitList(answer) {
+			if err := validate(value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return splitList(answer), err
+}
+
+func splitList(answer string) []string {
+	values := []string{}
+	for _, value := range strings.Split(answer, ",") {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func validateUsername(username string) error {
+	if !usernameRegex.MatchString(username) {
+		return fmt.Errorf("username '%s' is not valid GitHub username", username)
+	}
+
+	return nil
+}
+
+func validateURL(address string) error {
+	u, err := url.Parse(address)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("address '%s' is not a valid http(s) url", address)
+	}
+
+	return nil
+}
+
+func validateOrg(org string) error {
+	if !orgRegex.MatchString(org) {
+		return fmt.Errorf("org name '%s' is not in format <ORG>", org)
+	}
+
+	return nil
+}
+
+func validateRepo(repo string) error {
+	if !repoRegex.MatchString(repo) {
+		return fmt.Errorf("repo name '%s' is not in format <ORG>/<REPO>", repo)
+	}
+
+	return nil
+}
+
+func validateTemplate(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("template file '%s' does not exist", path)
+	}
+
+	if info.IsDir() {
+		return fmt.Errorf("template path '%s' points to the directory", path)
+	}
+
+	return nil
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/pPrecel/PKUP/cmd"
 	"github.com/pterm/pterm"
@@ -48,12 +51,23 @@ func main() {
 		Commands: []*cli.Command{
 			cmd.NewGenCommand(opts),
 			cmd.NewComposeCommand(opts),
+			cmd.NewInitCommand(opts),
+			cmd.NewRenderCommand(opts),
+			cmd.NewValidateCommand(opts),
+			cmd.NewSchemaCommand(opts),
 			cmd.NewVersionCommand(opts),
 			cmd.NewSendCommand(opts),
 		},
 	}
 
-	if err := app.Run(os.Args); err != nil {
+	// stop in-flight work on the first signal and exit immediately on the next one
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if err := app.RunContext(ctx, os.Args); err != nil {
 		log.Fatal("program error", log.Args("error", err))
 	}
 }
@@ -1,7 +1,11 @@
 package artifacts
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/pPrecel/PKUP/internal/file"
@@ -17,7 +21,7 @@ type Options struct {
 	Until   time.Time
 }
 
-func GenUserArtifactsToDir(client github.Client, opts Options) (*github.CommitList, error) {
+func GenUserArtifactsToDir(ctx context.Context, client github.Client, opts Options) (*github.CommitList, error) {
 	commits, err := client.ListRepoCommits(github.ListRepoCommitsOpts{
 		Org:     opts.Org,
 		Repo:    opts.Repo,
@@ -29,7 +33,7 @@ func GenUserArtifactsToDir(client github.Client, opts Options) (*github.CommitLi
 		return nil, fmt.Errorf("list users commits in repo '%s/%s' error: %s", opts.Org, opts.Repo, err.Error())
 	}
 
-	err = SaveDiffToFiles(client, commits, opts)
+	err = SaveDiffToFiles(ctx, client, commits, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -37,22 +41,94 @@ func GenUserArtifactsToDir(client github.Client, opts Options) (*github.CommitLi
 	return commits, nil
 }
 
-func SaveDiffToFiles(client github.Client, commits *github.CommitList, opts Options) error {
+// Diffs contains diffs of commits by the commit SHA
+type Diffs map[string]string
+
+func SaveDiffToFiles(ctx context.Context, client github.Client, commits *github.CommitList, opts Options) error {
+	diffs, err := GetDiffs(ctx, client, commits, opts)
+	if err != nil {
+		return err
+	}
+
+	_, err = SaveDiffs(ctx, commits, diffs, opts)
+	return err
+}
+
+// GetDiffs downloads diffs of all commits and fills their stats without saving any file
+// stops when the ctx is canceled
+func GetDiffs(ctx context.Context, client github.Client, commits *github.CommitList, opts Options) (Diffs, error) {
+	diffs := Diffs{}
 	for i := range commits.Commits {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		commit := commits.Commits[i]
 		diff, err := client.GetCommitContentDiff(commit, opts.Org, opts.Repo)
 		if err != nil {
-			return fmt.Errorf("get diff for repo '%s/%s' error: %s", opts.Org, opts.Repo, err.Error())
+			return nil, fmt.Errorf("get diff for repo '%s/%s' error: %s", opts.Org, opts.Repo, err.Error())
 		}
 
+		github.SetDiffStats(commit, diff)
+		diffs[commit.GetSHA()] = diff
+	}
+
+	return diffs, nil
+}
+
+// LoadDiffs reads diffs saved to the opts.Dir before and fills stats of commits
+// diffs of commits without saved files ( e.g. excluded by rules ) are downloaded
+func LoadDiffs(ctx context.Context, client github.Client, commits *github.CommitList, opts Options) (Diffs, error) {
+	diffs := Diffs{}
+	missing := &github.CommitList{}
+	for _, commit := range commits.Commits {
+		filename := file.BuildDiffFilename(commit.GetSHA(), opts.Org, opts.Repo)
+		data, err := os.ReadFile(filepath.Join(opts.Dir, filename))
+		if errors.Is(err, os.ErrNotExist) {
+			missing.Commits = append(missing.Commits, commit)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read file '%s' error: %s", filename, err.Error())
+		}
+
+		github.SetDiffStats(commit, string(data))
+		diffs[commit.GetSHA()] = string(data)
+	}
+
+	missingDiffs, err := GetDiffs(ctx, client, missing, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for sha, diff := range missingDiffs {
+		diffs[sha] = diff
+	}
+
+	return diffs, nil
+}
+
+// SaveDiffs saves not empty diffs of given commits to the opts.Dir
+// stops when the ctx is canceled ( every file is saved completely or not at all )
+// returns paths of saved files
+func SaveDiffs(ctx context.Context, commits *github.CommitList, diffs Diffs, opts Options) ([]string, error) {
+	paths := []string{}
+	for _, commit := range commits.Commits {
+		if err := ctx.Err(); err != nil {
+			return paths, err
+		}
+
+		diff := diffs[commit.GetSHA()]
 		if diff != "" {
 			filename := file.BuildDiffFilename(commit.GetSHA(), opts.Org, opts.Repo)
-			err = file.Create(opts.Dir, filename, diff)
+			err := file.Create(opts.Dir, filename, diff)
 			if err != nil {
-				return fmt.Errorf("save file '%s' error: %s", filename, err.Error())
+				return paths, fmt.Errorf("save file '%s' error: %s", filename, err.Error())
 			}
+
+			paths = append(paths, filepath.Join(opts.Dir, filename))
 		}
 	}
 
-	return nil
+	return paths, nil
 }
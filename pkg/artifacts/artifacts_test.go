@@ -1,6 +1,7 @@
 package artifacts
 
 import (
+	"context"
 	"errors"
 	"os"
 	"path"
@@ -18,7 +19,7 @@ import (
 func TestGenUserArtifactsToDir(t *testing.T) {
 	t.Run("generate diff", func(t *testing.T) {
 		tmpDir := t.TempDir()
-		diff := "+ anything"
+		diff := "diff --git a/file.go b/file.go\n@@ -1 +1 @@\n+ anything\n"
 		testCommits := &github.CommitList{
 			Commits: []*go_github.RepositoryCommit{
 				{
@@ -46,7 +47,7 @@ func TestGenUserArtifactsToDir(t *testing.T) {
 			Until:   time.Time{},
 		}, mock.Anything).Return(testCommits, nil).Once()
 
-		commitList, err := GenUserArtifactsToDir(clientMock, Options{
+		commitList, err := GenUserArtifactsToDir(context.Background(), clientMock, Options{
 			Org:     "test-org",
 			Repo:    "test-repo",
 			Authors: []string{"test-username"},
@@ -57,6 +58,8 @@ func TestGenUserArtifactsToDir(t *testing.T) {
 
 		require.NoError(t, err)
 		require.ElementsMatch(t, testCommits.Commits, commitList.Commits)
+		require.Equal(t, 1, commitList.Commits[0].GetStats().GetAdditions())
+		require.Len(t, commitList.Commits[0].Files, 1)
 
 		expectedDiffFile := path.Join(tmpDir, "test-org_test-repo_sha1.diff")
 		require.FileExists(t, expectedDiffFile)
@@ -80,7 +83,7 @@ func TestGenUserArtifactsToDir(t *testing.T) {
 			Until:   time.Time{},
 		}, mock.Anything).Return(nil, errors.New("test error")).Once()
 
-		prs, err := GenUserArtifactsToDir(clientMock, Options{
+		prs, err := GenUserArtifactsToDir(context.Background(), clientMock, Options{
 			Org:     "test-org",
 			Repo:    "test-repo",
 			Authors: []string{"test-username"},
@@ -109,7 +112,7 @@ func TestGenUserArtifactsToDir(t *testing.T) {
 			},
 		}, nil).Once()
 
-		prs, err := GenUserArtifactsToDir(clientMock, Options{
+		prs, err := GenUserArtifactsToDir(context.Background(), clientMock, Options{
 			Org:     "test-org",
 			Repo:    "test-repo",
 			Authors: []string{"test-username"},
@@ -122,3 +125,75 @@ func TestGenUserArtifactsToDir(t *testing.T) {
 		require.Empty(t, prs)
 	})
 }
+
+func TestSaveDiffs(t *testing.T) {
+	t.Run("save diffs of given commits only", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		commits := &github.CommitList{
+			Commits: []*go_github.RepositoryCommit{
+				{SHA: ptr.To("sha1")},
+				{SHA: ptr.To("sha2")},
+			},
+		}
+
+		paths, err := SaveDiffs(context.Background(), commits, Diffs{
+			"sha1": "diff 1",
+			// empty diff
+			"sha2": "",
+			// commit not selected
+			"sha3": "diff 3",
+		}, Options{
+			Org:  "test-org",
+			Repo: "test-repo",
+			Dir:  tmpDir,
+		})
+		require.NoError(t, err)
+
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, "test-org_test-repo_sha1.diff", entries[0].Name())
+		require.Equal(t, []string{path.Join(tmpDir, "test-org_test-repo_sha1.diff")}, paths)
+	})
+}
+
+func TestLoadDiffs(t *testing.T) {
+	t.Run("load saved diffs and download missing ones", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		diff := "diff --git a/file.go b/file.go\n@@ -1 +1 @@\n+ anything\n"
+		require.NoError(t, os.WriteFile(path.Join(tmpDir, "test-org_test-repo_sha1.diff"), []byte(diff), 0644))
+		commits := &github.CommitList{
+			Commits: []*go_github.RepositoryCommit{
+				{SHA: ptr.To("sha1")},
+				{SHA: ptr.To("sha2")},
+			},
+		}
+
+		clientMock := automock.NewClient(t)
+		clientMock.On("GetCommitContentDiff", commits.Commits[1], "test-org", "test-repo").Return("", nil).Once()
+
+		diffs, err := LoadDiffs(context.Background(), clientMock, commits, Options{
+			Org:  "test-org",
+			Repo: "test-repo",
+			Dir:  tmpDir,
+		})
+		require.NoError(t, err)
+		require.Equal(t, Diffs{"sha1": diff, "sha2": ""}, diffs)
+		require.Equal(t, 1, commits.Commits[0].GetStats().GetAdditions())
+	})
+}
+
+func TestGetDiffs(t *testing.T) {
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		diffs, err := GetDiffs(ctx, automock.NewClient(t), &github.CommitList{
+			Commits: []*go_github.RepositoryCommit{
+				{SHA: ptr.To("sha1")},
+			},
+		}, Options{})
+		require.ErrorIs(t, err, context.Canceled)
+		require.Nil(t, diffs)
+	})
+}
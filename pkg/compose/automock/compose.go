@@ -15,17 +15,29 @@ type Compose struct {
 }
 
 // ForConfig provides a mock function with given fields: _a0, _a1
-func (_m *Compose) ForConfig(_a0 *config.Config, _a1 compose.Options) error {
+func (_m *Compose) ForConfig(_a0 *config.Config, _a1 compose.Options) (*compose.Summary, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*config.Config, compose.Options) error); ok {
+	var r0 *compose.Summary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*config.Config, compose.Options) (*compose.Summary, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(*config.Config, compose.Options) *compose.Summary); ok {
 		r0 = rf(_a0, _a1)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*compose.Summary)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*config.Config, compose.Options) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
 // NewCompose creates a new instance of Compose. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -6,22 +6,23 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/hashicorp/go-multierror"
+	go_github "github.com/google/go-github/v53/github"
 	"github.com/pPrecel/PKUP/internal/view"
 	"github.com/pPrecel/PKUP/pkg/artifacts"
 	"github.com/pPrecel/PKUP/pkg/compose/utils"
 	"github.com/pPrecel/PKUP/pkg/config"
 	"github.com/pPrecel/PKUP/pkg/github"
 	"github.com/pPrecel/PKUP/pkg/report"
+	"github.com/pPrecel/PKUP/pkg/rules"
 	"github.com/pterm/pterm"
 )
 
 //go:generate mockery --name=Compose --output=automock --outpkg=automock --case=underscore
 type Compose interface {
-	ForConfig(*config.Config, Options) error
+	ForConfig(*config.Config, Options) (*Summary, error)
 }
 
 type compose struct {
@@ -30,6 +31,27 @@ type compose struct {
 	buildClient utils.BuildClientFunc
 
 	repoCommitsLister utils.LazyCommitsLister
+	// limits concurrent API calls of all users together
+	pool *utils.Pool
+	// users and repos composed in the previous and the current run
+	state *state
+	// files that would be written by every user in the dry run
+	dryRunPlans *dryRunPlans
+	// pull requests and issues used to enrich commits of all users
+	pullRequests *pullRequestsCache
+	// stops composing for all users ( used by the fail-fast policy )
+	cancel context.CancelCauseFunc
+}
+
+// repoOutput contains everything composed for the user from a single repo
+type repoOutput struct {
+	result   report.Result
+	diffs    artifacts.Diffs
+	excluded []*view.RepoCommit
+	// paths of saved diffs
+	artifacts []string
+	// set when the repo is skipped by the skip-repo policy
+	err error
 }
 
 func New(ctx context.Context, logger *pterm.Logger) Compose {
@@ -44,21 +66,78 @@ type Options struct {
 	Since time.Time
 	Until time.Time
 	Ci    bool
+	// max number of concurrent API calls ( default: utils.DefaultParallelism )
+	Parallelism int
+	// path to the file with completed users and repos used to resume interrupted run ( empty to disable )
+	StatePath string
+	// resolve clients, repos, branches, signatures and commits without downloading diffs and writing any file
+	DryRun bool
+	// what to do when composing fails for the repo ( one of FailurePolicies, default: skip-user )
+	FailurePolicy string
+	// called with all found commits before saving any file
+	// returned results are used to generate artifacts and reports
+	SelectCommits func([]report.Result) ([]report.Result, error)
 }
 
-func (c *compose) ForConfig(config *config.Config, opts Options) error {
+func (c *compose) ForConfig(config *config.Config, opts Options) (*Summary, error) {
+	if err := ValidateFailurePolicy(opts.FailurePolicy); err != nil {
+		return nil, err
+	}
+
+	// the parent ctx is canceled only when the program is interrupted
+	interruptCtx := c.ctx
+	c.ctx, c.cancel = context.WithCancelCause(interruptCtx)
+	defer func() {
+		c.cancel(nil)
+		c.ctx = interruptCtx
+	}()
+
 	taskView := view.NewMultiTaskView(c.logger, opts.Ci)
 	viewLogger := c.logger.WithWriter(taskView.NewWriter())
 
-	remoteClients, err := utils.BuildClients(c.ctx, c.logger, config, c.buildClient)
+	// all clients share the counter to sum API calls of the whole run
+	apiCalls := &atomic.Int64{}
+	buildClient := func(ctx context.Context, logger *pterm.Logger, clientOpts github.ClientOpts) (github.Client, error) {
+		clientOpts.APICalls = apiCalls
+		return c.buildClient(ctx, logger, clientOpts)
+	}
+
+	remoteClients, err := utils.BuildClients(c.ctx, c.logger, config, buildClient)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	stateConfigHash, err := configHash(config)
+	if err != nil {
+		return nil, err
+	}
+
+	c.state, err = loadState(opts.StatePath, opts.Since, opts.Until, stateConfigHash)
+	if err != nil {
+		return nil, err
 	}
 
-	c.repoCommitsLister = utils.NewLazyRepoCommitsLister(c.logger, remoteClients)
+	c.dryRunPlans = newDryRunPlans()
+	c.pullRequests = newPullRequestsCache()
+	c.pool = utils.NewPool(opts.Parallelism)
+	c.repoCommitsLister = utils.NewLazyRepoCommitsLister(c.ctx, c.logger, remoteClients, c.pool)
 
+	// every user goroutine writes only to its own summary
+	summary := &Summary{
+		Users: make([]UserSummary, len(config.Reports)),
+	}
+	defer func() {
+		summary.APICalls = apiCalls.Load()
+	}()
 	for i := range config.Reports {
 		user := config.Reports[i]
+		userSummary := &summary.Users[i]
+		userSummary.User = getUsernames(user)
+		if c.state.isUserDone(getUsernames(user)) {
+			c.logger.Info(fmt.Sprintf("skipping %s composed in the previous run", getUsernames(user)))
+			userSummary.Status = UserStatusSkipped
+			continue
+		}
 
 		valChan := make(chan []*view.RepoCommit)
 		errChan := make(chan error)
@@ -66,124 +145,415 @@ func (c *compose) ForConfig(config *config.Config, opts Options) error {
 
 		go func() {
 			viewLogger.Debug("compose for user", viewLogger.Args("user", getUsernames(user)))
-			commitList, err := c.composeForUser(remoteClients, &user, config, &opts)
+			commitList, err := c.composeForUser(remoteClients, &user, config, &opts, userSummary)
 			if err != nil {
+				err = c.handleUserError(getUsernames(user), err, &opts)
+				userSummary.Status = UserStatusFailed
+				userSummary.Errors = append(userSummary.Errors, err.Error())
 				errChan <- err
 				return
 			}
 
+			userSummary.Status = UserStatusSucceeded
+			if len(userSummary.Errors) > 0 {
+				userSummary.Status = UserStatusPartial
+			}
+
 			valChan <- commitList
 		}()
 	}
 
-	return taskView.Run()
+	if err := taskView.Run(); err != nil {
+		return nil, err
+	}
+
+	if interruptCtx.Err() != nil {
+		if opts.StatePath == "" {
+			return summary, fmt.Errorf("composing interrupted: %s", interruptCtx.Err().Error())
+		}
+
+		return summary, fmt.Errorf("composing interrupted, completed users and repos saved to '%s' - run the same command again to resume", opts.StatePath)
+	}
+
+	if opts.DryRun {
+		c.dryRunPlans.print(c.logger, config.Reports)
+		return summary, nil
+	}
+
+	for i := range config.Reports {
+		if !c.state.isUserDone(getUsernames(config.Reports[i])) {
+			// keep the state to retry only failed users
+			return summary, nil
+		}
+	}
+
+	return summary, c.state.remove()
+}
+
+// handleUserError stops composing for other users in the fail-fast policy
+// returns the reason of the failure for users canceled because of the failure of another user
+func (c *compose) handleUserError(user string, err error, opts *Options) error {
+	if c.ctx.Err() != nil && context.Cause(c.ctx) != c.ctx.Err() {
+		return fmt.Errorf("canceled by the %s policy: %s", FailurePolicyFailFast, context.Cause(c.ctx).Error())
+	}
+
+	if opts.FailurePolicy == FailurePolicyFailFast {
+		c.cancel(fmt.Errorf("failed to compose for %s: %s", user, err.Error()))
+	}
+
+	return err
 }
 
-func (c *compose) composeForUser(remoteClients *utils.RemoteClients, user *config.Report, config *config.Config, opts *Options) ([]*view.RepoCommit, error) {
-	outputDir, err := sanitizeOutputDir(user.OutputDir)
+// composeForUser saves artifacts and the report of the user
+// repos skipped by the skip-repo policy are added to the summary errors
+func (c *compose) composeForUser(remoteClients *utils.RemoteClients, user *config.Report, config *config.Config, opts *Options, summary *UserSummary) ([]*view.RepoCommit, error) {
+	outputDir, err := sanitizeOutputDir(user.OutputDir, !opts.DryRun)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sanitize path '%s': %s", user.OutputDir, err.Error())
 	}
+	summary.OutputDir = outputDir
+
+	// report can override the template, orgs, repos and period
+	config = config.ForReport(user)
+	since, until, err := user.Period.Bounds(opts.Since, opts.Until)
+	if err != nil {
+		return nil, err
+	}
 
 	urlAuthors, err := utils.BuildUrlAuthors(remoteClients, user.Signatures)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list user signatures: %s", err.Error())
 	}
 
-	repoCommits, err := c.repoCommitsLister.List(config, opts.Since, opts.Until)
+	repoCommits, err := c.repoCommitsLister.List(config, since, until)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list commits: %s", err.Error())
 	}
 
-	wg := sync.WaitGroup{}
-	var errors error
-	commitList := []*view.RepoCommit{}
-	results := []report.Result{}
-	for i := range repoCommits.RepoCommits {
+	for _, failed := range repoCommits.Failed {
+		// the error contains the repo name
+		if opts.FailurePolicy != FailurePolicySkipRepo {
+			return nil, failed.Err
+		}
+
+		c.skipRepo(summary, failed.Err)
+	}
+
+	filter, err := rules.New(toRules(config.Rules), config.Bots.Logins)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build rules: %s", err.Error())
+	}
+
+	// every job writes only to its own index so results keep the order of listed repos
+	username := getUsernames(*user)
+	repoOutputs := make([]*repoOutput, len(repoCommits.RepoCommits))
+	composeRepo := func(i int) (*repoOutput, error) {
 		repo := repoCommits.RepoCommits[i]
-		wg.Add(1)
-		go func() {
-			authors := urlAuthors.GetAuthors(repo.EnterpriseUrl)
-			userCommits := github.CommitList{
-				Commits: github.GetUserCommits(repo.Commits.Commits, authors),
+		repoName := fmt.Sprintf("%s/%s", repo.Org, repo.Repo)
+		authors := urlAuthors.GetAuthors(repo.EnterpriseUrl)
+		bots := github.BotOptions{
+			Mode:   config.Bots.Mode,
+			Logins: config.Bots.Logins,
+		}
+		userCommits := github.CommitList{
+			Commits: github.GetUserCommits(repo.Commits.Commits, authors, bots),
+		}
+
+		if opts.DryRun {
+			// diffs are not downloaded so rules based on diff stats can't be applied
+			return &repoOutput{
+				result: report.Result{
+					Org:        repo.Org,
+					Repo:       repo.Repo,
+					CommitList: userCommits,
+				},
+			}, nil
+		}
+
+		artifactsOpts := artifacts.Options{
+			Org:     repo.Org,
+			Repo:    repo.Repo,
+			Authors: authors,
+			Dir:     outputDir,
+			Since:   since,
+			Until:   until,
+		}
+
+		getDiffs := artifacts.GetDiffs
+		if c.state.isRepoDone(username, repoName) {
+			// diffs were saved in the previous run
+			getDiffs = artifacts.LoadDiffs
+		}
+
+		repoDiffs, diffErr := getDiffs(c.ctx, remoteClients.Get(repo.EnterpriseUrl), &userCommits, artifactsOpts)
+		if diffErr != nil {
+			return nil, fmt.Errorf("failed to generate artifacts for repo '%s': %s", repo.Repo, diffErr.Error())
+		}
+
+		// url := repo.enterpriseUrl
+		// if url == "" {
+		// 	url = "https://github.com"
+		// }
+
+		// exclude commits based on rules ( diff stats are required )
+		filtered := filter.Apply(userCommits.Commits)
+		userCommits.Commits = filtered.Included
+
+		pullRequests := map[string][]github.PullRequest{}
+		if user.Enrich {
+			pullRequests = c.listPullRequests(remoteClients.Get(repo.EnterpriseUrl), repo.EnterpriseUrl, repo.Org, repo.Repo, &userCommits)
+		}
+
+		botCommits := map[string]bool{}
+		if bots.Mode == github.BotCommitsFlag {
+			for _, commit := range userCommits.Commits {
+				if github.IsBotCommit(commit, bots.Logins) {
+					botCommits[commit.GetSHA()] = true
+				}
 			}
+		}
 
-			saveErr := artifacts.SaveDiffToFiles(remoteClients.Get(repo.EnterpriseUrl), &userCommits, artifacts.Options{
-				Org:     repo.Org,
-				Repo:    repo.Repo,
-				Authors: authors,
-				Dir:     outputDir,
-				Since:   opts.Since,
-				Until:   opts.Until,
-			})
+		savedArtifacts := []string{}
+		if opts.SelectCommits == nil {
+			// save artifacts right away to resume from this repo when interrupted
+			var saveErr error
+			savedArtifacts, saveErr = artifacts.SaveDiffs(c.ctx, &userCommits, repoDiffs, artifactsOpts)
 			if saveErr != nil {
-				errors = multierror.Append(errors, fmt.Errorf(
-					"failed to generate artifacts for repo '%s': %s", repo.Repo, saveErr.Error(),
-				))
-			} else {
-				// url := repo.enterpriseUrl
-				// if url == "" {
-				// 	url = "https://github.com"
-				// }
-
-				for _, commit := range userCommits.Commits {
-					repoCommit := &view.RepoCommit{
-						Org:     repo.Org,
-						Repo:    repo.Repo,
-						Message: strings.Split(commit.Commit.GetMessage(), "\n")[0],
-						SHA:     commit.GetSHA(),
-					}
-					commitList = append(commitList, repoCommit)
-
-					c.logger.Trace(
-						fmt.Sprintf("found commit for user %s", getUsernames(*user)),
-						c.logger.Args(
-							"org/repo", fmt.Sprintf("%s/%s", repoCommit.Org, repoCommit.Repo),
-							"sha", repoCommit.SHA,
-							"message", repoCommit.Message,
-						),
-					)
-				}
+				return nil, fmt.Errorf("failed to generate artifacts for repo '%s': %s", repo.Repo, saveErr.Error())
+			}
 
-				results = append(results, report.Result{
-					Org:  repo.Org,
-					Repo: repo.Repo,
-					// URL:        url,
-					CommitList: userCommits,
-				})
+			if err := c.state.setRepoDone(username, repoName); err != nil {
+				return nil, err
 			}
+		}
 
-			wg.Done()
-		}()
+		output := &repoOutput{
+			diffs:     repoDiffs,
+			artifacts: savedArtifacts,
+			result: report.Result{
+				Org:  repo.Org,
+				Repo: repo.Repo,
+				// URL:        url,
+				CommitList:   userCommits,
+				PullRequests: pullRequests,
+				BotCommits:   botCommits,
+			},
+		}
+		for _, excluded := range filtered.Excluded {
+			output.excluded = append(output.excluded, toViewRepoCommit(repo.Org, repo.Repo, excluded.Commit, excluded.Rule))
+		}
+
+		return output, nil
+	}
+
+	err = c.pool.Run(c.ctx, len(repoCommits.RepoCommits), func(i int) error {
+		output, err := composeRepo(i)
+		if err != nil && opts.FailurePolicy == FailurePolicySkipRepo && c.ctx.Err() == nil {
+			output = &repoOutput{err: err}
+			err = nil
+		}
+
+		repoOutputs[i] = output
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	wg.Wait()
-	if errors != nil {
-		return nil, errors
+	results := []report.Result{}
+	excludedList := []*view.RepoCommit{}
+	diffs := map[string]artifacts.Diffs{}
+	for _, output := range repoOutputs {
+		if output.err != nil {
+			c.skipRepo(summary, output.err)
+			continue
+		}
+
+		results = append(results, output.result)
+		summary.Artifacts = append(summary.Artifacts, output.artifacts...)
+		excludedList = append(excludedList, output.excluded...)
+		diffs[fmt.Sprintf("%s/%s", output.result.Org, output.result.Repo)] = output.diffs
 	}
 
-	if config.Template != "" {
-		templatePath, err := filepath.Abs(config.Template)
+	if opts.SelectCommits != nil {
+		// let user decide which commits should be part of the report
+		results, err = opts.SelectCommits(results)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to select commits: %s", err.Error())
 		}
+	}
 
-		err = report.Render(report.Options{
-			OutputDir:    outputDir,
-			TemplatePath: templatePath,
-			PeriodFrom:   opts.Since,
-			PeriodTill:   opts.Until,
-			Results:      results,
-			CustomValues: user.ExtraFields,
-		})
+	commitList := []*view.RepoCommit{}
+	for i := range results {
+		result := results[i]
+		if opts.SelectCommits != nil {
+			// artifacts are saved only for selected commits
+			savedArtifacts, err := artifacts.SaveDiffs(c.ctx, &result.CommitList, diffs[fmt.Sprintf("%s/%s", result.Org, result.Repo)], artifacts.Options{
+				Org:  result.Org,
+				Repo: result.Repo,
+				Dir:  outputDir,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate artifacts for repo '%s': %s", result.Repo, err.Error())
+			}
+
+			summary.Artifacts = append(summary.Artifacts, savedArtifacts...)
+		}
+
+		summary.Commits += len(result.CommitList.Commits)
+		for _, commit := range result.CommitList.Commits {
+			repoCommit := toViewRepoCommit(result.Org, result.Repo, commit, "")
+			commitList = append(commitList, repoCommit)
+
+			c.logger.Trace(
+				fmt.Sprintf("found commit for user %s", getUsernames(*user)),
+				c.logger.Args(
+					"org/repo", fmt.Sprintf("%s/%s", repoCommit.Org, repoCommit.Repo),
+					"sha", repoCommit.SHA,
+					"message", repoCommit.Message,
+				),
+			)
+		}
+	}
+
+	for _, excluded := range excludedList {
+		c.logger.Trace(
+			fmt.Sprintf("excluded commit for user %s", getUsernames(*user)),
+			c.logger.Args(
+				"org/repo", fmt.Sprintf("%s/%s", excluded.Org, excluded.Repo),
+				"sha", excluded.SHA,
+				"rule", excluded.ExcludedBy,
+			),
+		)
+	}
+	commitList = append(commitList, excludedList...)
+
+	if err := c.ctx.Err(); err != nil {
+		// don't render the report from incomplete data
+		return nil, err
+	}
+
+	reportOpts, err := buildReportOpts(config, user, outputDir, since, until, results)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.DryRun {
+		plan, err := buildDryRunPlan(outputDir, results, reportOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to plan report: %s", err.Error())
+		}
+
+		c.dryRunPlans.set(username, plan)
+		summary.Artifacts = plan.artifacts
+		summary.Reports = plan.reports
+		return commitList, nil
+	}
+
+	if rendersReports(reportOpts) {
+		output, err := report.Render(*reportOpts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to render report: %s", err.Error())
 		}
+
+		summary.Reports = output.Files
+
+		for _, warning := range output.Warnings {
+			c.logger.Warn("report validation", c.logger.Args(
+				"user", getUsernames(*user),
+				"issue", warning,
+			))
+		}
+	}
+
+	// the manifest is saved next to artifacts also without reports so they can be rendered later by the render command
+	if err := report.SaveManifest(*reportOpts); err != nil {
+		return nil, fmt.Errorf("failed to save report manifest: %s", err.Error())
+	}
+	summary.Reports = append(summary.Reports, filepath.Join(outputDir, report.ManifestFilename))
+
+	if len(summary.Errors) > 0 {
+		// skipped repos are retried in the next run
+		return commitList, nil
+	}
+
+	if err := c.state.setUserDone(username); err != nil {
+		return nil, err
 	}
 
 	return commitList, nil
 }
 
+func (c *compose) skipRepo(summary *UserSummary, err error) {
+	c.logger.Warn("skipping repo", c.logger.Args("user", summary.User, "error", err.Error()))
+	summary.Errors = append(summary.Errors, err.Error())
+}
+
+// buildReportOpts returns options of the user report or nil when neither template nor formats are configured
+func buildReportOpts(config *config.Config, user *config.Report, outputDir string, since, until time.Time, results []report.Result) (*report.Options, error) {
+	templatePath := ""
+	if config.Template != "" {
+		var err error
+		templatePath, err = filepath.Abs(config.Template)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &report.Options{
+		OutputDir:     outputDir,
+		TemplatePath:  templatePath,
+		Formats:       user.Formats,
+		PDFLayoutPath: config.PDFLayout,
+		GroupBy:       user.GroupBy,
+		Locales:       user.Locales,
+		PeriodFrom:    since,
+		PeriodTill:    until,
+		Results:       results,
+		CustomValues:  user.ExtraFields,
+		Validation: report.ValidationOptions{
+			Mode:     user.Validation.Mode,
+			Patterns: user.Validation.Patterns,
+		},
+	}, nil
+}
+
+// rendersReports returns true when the template or report formats are configured
+// otherwise only artifacts and the manifest are saved
+func rendersReports(reportOpts *report.Options) bool {
+	return reportOpts.TemplatePath != "" || len(reportOpts.Formats) > 0
+}
+
+func toViewRepoCommit(org, repo string, commit *go_github.RepositoryCommit, excludedBy string) *view.RepoCommit {
+	return &view.RepoCommit{
+		Org:        org,
+		Repo:       repo,
+		Message:    strings.Split(commit.GetCommit().GetMessage(), "\n")[0],
+		SHA:        commit.GetSHA(),
+		Additions:  commit.GetStats().GetAdditions(),
+		Deletions:  commit.GetStats().GetDeletions(),
+		ExcludedBy: excludedBy,
+	}
+}
+
+func toRules(configRules []config.Rule) []rules.Rule {
+	list := []rules.Rule{}
+	for _, rule := range configRules {
+		list = append(list, rules.Rule{
+			Name:        rule.Name,
+			Message:     rule.Message,
+			Paths:       rule.Paths,
+			BotAuthor:   rule.BotAuthor,
+			Merge:       rule.Merge,
+			RevertPairs: rule.RevertPairs,
+			MinLines:    rule.MinLines,
+			MaxLines:    rule.MaxLines,
+		})
+	}
+
+	return list
+}
+
 func getUsernames(user config.Report) string {
 	users := []string{}
 	for _, u := range user.Signatures {
@@ -193,9 +563,10 @@ func getUsernames(user config.Report) string {
 	return strings.Join(users, ", ")
 }
 
-func sanitizeOutputDir(dir string) (string, error) {
+// sanitizeOutputDir returns the absolute path of the dir and creates it when create is true
+func sanitizeOutputDir(dir string, create bool) (string, error) {
 	outputDir, err := filepath.Abs(dir)
-	if err != nil {
+	if err != nil || !create {
 		return outputDir, err
 	}
 
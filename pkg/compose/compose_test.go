@@ -0,0 +1,217 @@
+package compose
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	go_github "github.com/google/go-github/v53/github"
+	"github.com/pPrecel/PKUP/pkg/config"
+	"github.com/pPrecel/PKUP/pkg/github"
+	"github.com/pPrecel/PKUP/pkg/github/automock"
+	"github.com/pPrecel/PKUP/pkg/report"
+	"github.com/pterm/pterm"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func Test_compose_ForConfig(t *testing.T) {
+	since := time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 2, 18, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		failurePolicy string
+		want          []UserSummary
+	}{
+		{
+			name:          "skip failed repo",
+			failurePolicy: FailurePolicySkipRepo,
+			want: []UserSummary{
+				{
+					User:      "test-user",
+					Status:    UserStatusPartial,
+					Commits:   1,
+					Artifacts: []string{"test-org_ok-repo_sha1.diff"},
+					Reports:   []string{report.ManifestFilename},
+					Errors:    []string{"test error"},
+				},
+			},
+		},
+		{
+			name:          "skip user with failed repo",
+			failurePolicy: FailurePolicySkipUser,
+			want: []UserSummary{
+				{User: "test-user", Status: UserStatusFailed, Errors: []string{"test error"}},
+			},
+		},
+		{
+			name:          "stop on first failure",
+			failurePolicy: FailurePolicyFailFast,
+			want: []UserSummary{
+				{User: "test-user", Status: UserStatusFailed, Errors: []string{"test error"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientMock := fixClientMock(t)
+			c := fixCompose(clientMock)
+			outputDir := t.TempDir()
+
+			summary, err := c.ForConfig(fixConfig(outputDir), Options{
+				Since:         since,
+				Until:         until,
+				Ci:            true,
+				FailurePolicy: tt.failurePolicy,
+			})
+			require.NoError(t, err)
+
+			for i := range tt.want {
+				tt.want[i].OutputDir = outputDir
+				for j := range tt.want[i].Artifacts {
+					tt.want[i].Artifacts[j] = filepath.Join(outputDir, tt.want[i].Artifacts[j])
+				}
+				for j := range tt.want[i].Reports {
+					tt.want[i].Reports[j] = filepath.Join(outputDir, tt.want[i].Reports[j])
+				}
+			}
+			require.Equal(t, tt.want, summary.Users)
+		})
+	}
+
+	t.Run("unsupported failure policy", func(t *testing.T) {
+		c := fixCompose(automock.NewClient(t))
+
+		summary, err := c.ForConfig(fixConfig(t.TempDir()), Options{
+			FailurePolicy: "skip-all",
+		})
+		require.Error(t, err)
+		require.Nil(t, summary)
+	})
+}
+
+func Test_compose_ForConfig_sharedCommits(t *testing.T) {
+	t.Run("compose the same commits for two users", func(t *testing.T) {
+		// authored by test-user and committed with the other-user name
+		sharedCommit := &go_github.RepositoryCommit{
+			SHA:    ptr.To("sha1"),
+			Author: &go_github.User{Login: ptr.To("test-user")},
+			Commit: &go_github.Commit{
+				Message: ptr.To("test commit"),
+				Author:  &go_github.CommitAuthor{Name: ptr.To("other-user")},
+			},
+		}
+
+		clientMock := automock.NewClient(t)
+		clientMock.On("GetUserSignatures", "test-user").Return([]string{"test-user"}, nil).Once()
+		clientMock.On("GetUserSignatures", "other-user").Return([]string{"other-user"}, nil).Once()
+		// commits are listed once and shared between users
+		clientMock.On("ListRepoCommits", mock.Anything).Return(&github.CommitList{
+			Commits: []*go_github.RepositoryCommit{sharedCommit},
+		}, nil).Once()
+		clientMock.On("GetCommitContentDiff", mock.Anything, "test-org", "ok-repo").
+			Return("diff --git a/main.go b/main.go\n@@ -1 +1 @@\n+ anything\n", nil).Twice()
+
+		testUserDir := t.TempDir()
+		otherUserDir := t.TempDir()
+		cfg := &config.Config{
+			Repos: []config.Remote{
+				{Name: "test-org/ok-repo"},
+			},
+			Reports: []config.Report{
+				{
+					OutputDir:  testUserDir,
+					Signatures: []config.Signature{{Username: "test-user"}},
+				},
+				{
+					OutputDir:  otherUserDir,
+					Signatures: []config.Signature{{Username: "other-user"}},
+				},
+			},
+		}
+
+		summary, err := fixCompose(clientMock).ForConfig(cfg, Options{
+			Ci: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []UserSummary{
+			{
+				User:      "test-user",
+				Status:    UserStatusSucceeded,
+				Commits:   1,
+				OutputDir: testUserDir,
+				Artifacts: []string{filepath.Join(testUserDir, "test-org_ok-repo_sha1.diff")},
+				Reports:   []string{filepath.Join(testUserDir, report.ManifestFilename)},
+			},
+			{
+				User:      "other-user",
+				Status:    UserStatusSucceeded,
+				Commits:   1,
+				OutputDir: otherUserDir,
+				Artifacts: []string{filepath.Join(otherUserDir, "test-org_ok-repo_sha1.diff")},
+				Reports:   []string{filepath.Join(otherUserDir, report.ManifestFilename)},
+			},
+		}, summary.Users)
+
+		// stats are filled only in copies owned by users
+		require.Nil(t, sharedCommit.Stats)
+		require.Nil(t, sharedCommit.Files)
+	})
+}
+
+func fixCompose(client github.Client) *compose {
+	logger := pterm.DefaultLogger.WithWriter(io.Discard)
+	return &compose{
+		ctx:    context.Background(),
+		logger: logger,
+		buildClient: func(_ context.Context, _ *pterm.Logger, _ github.ClientOpts) (github.Client, error) {
+			return client, nil
+		},
+	}
+}
+
+func fixConfig(outputDir string) *config.Config {
+	return &config.Config{
+		Repos: []config.Remote{
+			{Name: "test-org/ok-repo"},
+			{Name: "test-org/failing-repo"},
+		},
+		Reports: []config.Report{
+			{
+				OutputDir: outputDir,
+				Signatures: []config.Signature{
+					{Username: "test-user"},
+				},
+			},
+		},
+	}
+}
+
+func fixClientMock(t *testing.T) *automock.Client {
+	clientMock := automock.NewClient(t)
+	clientMock.On("GetUserSignatures", "test-user").Return([]string{"test-user"}, nil).Once()
+	clientMock.On("ListRepoCommits", mock.MatchedBy(func(opts github.ListRepoCommitsOpts) bool {
+		return opts.Repo == "ok-repo"
+	})).Return(&github.CommitList{
+		Commits: []*go_github.RepositoryCommit{
+			{
+				SHA:    ptr.To("sha1"),
+				Author: &go_github.User{Login: ptr.To("test-user")},
+				Commit: &go_github.Commit{
+					Message: ptr.To("test commit"),
+				},
+			},
+		},
+	}, nil).Once()
+	clientMock.On("ListRepoCommits", mock.MatchedBy(func(opts github.ListRepoCommitsOpts) bool {
+		return opts.Repo == "failing-repo"
+	})).Return(nil, errors.New("test error")).Once()
+	clientMock.On("GetCommitContentDiff", mock.Anything, "test-org", "ok-repo").Return("diff --git a/main.go b/main.go\n", nil).Maybe()
+
+	return clientMock
+}
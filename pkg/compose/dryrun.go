@@ -0,0 +1,118 @@
+package compose
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/pPrecel/PKUP/internal/file"
+	"github.com/pPrecel/PKUP/pkg/config"
+	"github.com/pPrecel/PKUP/pkg/report"
+	"github.com/pterm/pterm"
+)
+
+// dryRunPlan describes what would be written for the user without the dry run
+type dryRunPlan struct {
+	outputDir string
+	// empty when only report formats are rendered
+	templatePath string
+	repos        []repoPlan
+	// diff files
+	artifacts []string
+	// reports and the manifest
+	reports []string
+}
+
+type repoPlan struct {
+	// in format <ORG>/<REPO>
+	name    string
+	commits int
+}
+
+// dryRunPlans collects plans of all users composed concurrently
+type dryRunPlans struct {
+	mutex sync.Mutex
+	// plans by users ( e.g. "pPrecel, pprecel-enterprise" )
+	plans map[string]*dryRunPlan
+}
+
+func newDryRunPlans() *dryRunPlans {
+	return &dryRunPlans{
+		plans: map[string]*dryRunPlan{},
+	}
+}
+
+func (p *dryRunPlans) set(user string, plan *dryRunPlan) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.plans[user] = plan
+}
+
+// print logs plans in order of reports from the config
+func (p *dryRunPlans) print(logger *pterm.Logger, reports []config.Report) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, user := range reports {
+		plan, ok := p.plans[getUsernames(user)]
+		if !ok {
+			continue
+		}
+
+		templatePath := plan.templatePath
+		if templatePath == "" {
+			templatePath = "none"
+		}
+
+		args := []interface{}{
+			"output dir", plan.outputDir,
+			"template", templatePath,
+		}
+		for _, repo := range plan.repos {
+			args = append(args, repo.name, fmt.Sprintf("%d commits", repo.commits))
+		}
+		for _, f := range plan.artifacts {
+			args = append(args, "file", f)
+		}
+		for _, f := range plan.reports {
+			args = append(args, "file", f)
+		}
+
+		logger.Info(fmt.Sprintf("dry run for %s, nothing was written", getUsernames(user)), logger.Args(args...))
+	}
+}
+
+// buildDryRunPlan returns files that would be saved for results
+// diff files are listed for all commits because diffs are not downloaded in the dry run
+func buildDryRunPlan(outputDir string, results []report.Result, reportOpts *report.Options) (*dryRunPlan, error) {
+	plan := &dryRunPlan{
+		outputDir: outputDir,
+	}
+
+	for _, result := range results {
+		plan.repos = append(plan.repos, repoPlan{
+			name:    fmt.Sprintf("%s/%s", result.Org, result.Repo),
+			commits: len(result.CommitList.Commits),
+		})
+
+		for _, commit := range result.CommitList.Commits {
+			plan.artifacts = append(plan.artifacts, filepath.Join(
+				outputDir, file.BuildDiffFilename(commit.GetSHA(), result.Org, result.Repo),
+			))
+		}
+	}
+
+	if rendersReports(reportOpts) {
+		reportFiles, err := report.OutputFiles(*reportOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		plan.templatePath = reportOpts.TemplatePath
+		plan.reports = append(plan.reports, reportFiles...)
+	}
+
+	plan.reports = append(plan.reports, filepath.Join(outputDir, report.ManifestFilename))
+	return plan, nil
+}
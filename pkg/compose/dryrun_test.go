@@ -0,0 +1,72 @@
+package compose
+
+import (
+	"path/filepath"
+	"testing"
+
+	go_github "github.com/google/go-github/v53/github"
+	"github.com/pPrecel/PKUP/pkg/github"
+	"github.com/pPrecel/PKUP/pkg/report"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func Test_buildDryRunPlan(t *testing.T) {
+	results := []report.Result{
+		{
+			Org:  "test-org",
+			Repo: "test-repo",
+			CommitList: github.CommitList{
+				Commits: []*go_github.RepositoryCommit{
+					{SHA: ptr.To("sha1")},
+					{SHA: ptr.To("sha2")},
+				},
+			},
+		},
+		{
+			Org:  "test-org",
+			Repo: "empty-repo",
+		},
+	}
+
+	t.Run("plan diffs and reports", func(t *testing.T) {
+		outputDir := t.TempDir()
+
+		plan, err := buildDryRunPlan(outputDir, results, &report.Options{
+			OutputDir: outputDir,
+			Formats:   []string{report.FormatMarkdown},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &dryRunPlan{
+			outputDir: outputDir,
+			repos: []repoPlan{
+				{name: "test-org/test-repo", commits: 2},
+				{name: "test-org/empty-repo", commits: 0},
+			},
+			artifacts: []string{
+				filepath.Join(outputDir, "test-org_test-repo_sha1.diff"),
+				filepath.Join(outputDir, "test-org_test-repo_sha2.diff"),
+			},
+			reports: []string{
+				filepath.Join(outputDir, "report.md"),
+				filepath.Join(outputDir, report.ManifestFilename),
+			},
+		}, plan)
+	})
+
+	t.Run("plan diffs and manifest only", func(t *testing.T) {
+		plan, err := buildDryRunPlan("/out", results[1:], &report.Options{OutputDir: "/out"})
+		require.NoError(t, err)
+		require.Empty(t, plan.artifacts)
+		require.Equal(t, []string{filepath.Join("/out", report.ManifestFilename)}, plan.reports)
+		require.Equal(t, []repoPlan{{name: "test-org/empty-repo", commits: 0}}, plan.repos)
+	})
+
+	t.Run("unsupported template", func(t *testing.T) {
+		_, err := buildDryRunPlan("/out", results, &report.Options{
+			OutputDir:    "/out",
+			TemplatePath: "/templates/report.pages",
+		})
+		require.Error(t, err)
+	})
+}
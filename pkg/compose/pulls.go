@@ -0,0 +1,121 @@
+package compose
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pPrecel/PKUP/pkg/github"
+)
+
+// pullRequestsCache keeps pull requests and issues fetched for all users in the run
+// commits of the same pull request reuse the pull request with its closed issues
+type pullRequestsCache struct {
+	mutex sync.Mutex
+	// by <ENTERPRISE_URL>/<ORG>/<REPO>#<NUMBER>
+	pullRequests map[string]github.PullRequest
+	// by <ENTERPRISE_URL>/<ORG>/<REPO>#<NUMBER> ( nil for issues that can't be fetched )
+	issues map[string]*github.Issue
+}
+
+func newPullRequestsCache() *pullRequestsCache {
+	return &pullRequestsCache{
+		pullRequests: map[string]github.PullRequest{},
+		issues:       map[string]*github.Issue{},
+	}
+}
+
+func (pc *pullRequestsCache) getPullRequest(key string) (github.PullRequest, bool) {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	pr, ok := pc.pullRequests[key]
+	return pr, ok
+}
+
+func (pc *pullRequestsCache) setPullRequest(key string, pr github.PullRequest) {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	pc.pullRequests[key] = pr
+}
+
+func (pc *pullRequestsCache) getIssue(key string) (*github.Issue, bool) {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	issue, ok := pc.issues[key]
+	return issue, ok
+}
+
+func (pc *pullRequestsCache) setIssue(key string, issue *github.Issue) {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	pc.issues[key] = issue
+}
+
+// listPullRequests returns pull requests associated with commits by the commit SHA
+// the report is still generated without pull requests data when the API call fails
+func (c *compose) listPullRequests(client github.Client, enterpriseUrl, org, repo string, commitList *github.CommitList) map[string][]github.PullRequest {
+	pullRequests := map[string][]github.PullRequest{}
+	for _, commit := range commitList.Commits {
+		if c.ctx.Err() != nil {
+			break
+		}
+
+		prs, err := client.ListCommitPullRequests(org, repo, commit.GetSHA())
+		if err != nil {
+			c.logger.Warn("failed to enrich commit", c.logger.Args(
+				"org/repo", fmt.Sprintf("%s/%s", org, repo),
+				"sha", commit.GetSHA(),
+				"error", err.Error(),
+			))
+			continue
+		}
+
+		for i := range prs {
+			key := fmt.Sprintf("%s/%s/%s#%d", enterpriseUrl, org, repo, prs[i].Number)
+			if pr, ok := c.pullRequests.getPullRequest(key); ok {
+				prs[i] = pr
+				continue
+			}
+
+			prs[i].Issues = c.getIssues(client, enterpriseUrl, prs[i].Issues)
+			c.pullRequests.setPullRequest(key, prs[i])
+		}
+
+		pullRequests[commit.GetSHA()] = prs
+	}
+
+	return pullRequests
+}
+
+// getIssues returns titles and URLs of referenced issues
+// issues that can't be fetched ( e.g. private or not existing ) are skipped
+func (c *compose) getIssues(client github.Client, enterpriseUrl string, refs []github.Issue) []github.Issue {
+	issues := []github.Issue{}
+	for _, ref := range refs {
+		key := fmt.Sprintf("%s/%s/%s#%d", enterpriseUrl, ref.Org, ref.Repo, ref.Number)
+		issue, ok := c.pullRequests.getIssue(key)
+		if !ok {
+			var err error
+			issue, err = client.GetIssue(ref.Org, ref.Repo, ref.Number)
+			if err != nil {
+				if c.ctx.Err() != nil {
+					// don't remember issues not fetched because of the interruption
+					continue
+				}
+
+				c.logger.Warn("skipping issue", c.logger.Args("error", err.Error()))
+			}
+
+			c.pullRequests.setIssue(key, issue)
+		}
+
+		if issue != nil {
+			issues = append(issues, *issue)
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,61 @@
+package compose
+
+import (
+	"errors"
+	"testing"
+
+	go_github "github.com/google/go-github/v53/github"
+	"github.com/pPrecel/PKUP/pkg/github"
+	"github.com/pPrecel/PKUP/pkg/github/automock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func Test_compose_listPullRequests(t *testing.T) {
+	t.Run("fetch the same pull request and issues once and skip missing issues", func(t *testing.T) {
+		clientMock := automock.NewClient(t)
+		for _, sha := range []string{"sha1", "sha2"} {
+			clientMock.On("ListCommitPullRequests", "test-org", "test-repo", sha).Return([]github.PullRequest{
+				{
+					Number: 123,
+					Title:  "Add new feature",
+					Issues: []github.Issue{
+						{Org: "test-org", Repo: "test-repo", Number: 12},
+						{Org: "test-org", Repo: "test-repo", Number: 99999},
+					},
+				},
+			}, nil).Once()
+		}
+		clientMock.On("GetIssue", "test-org", "test-repo", 12).Return(&github.Issue{
+			Org:    "test-org",
+			Repo:   "test-repo",
+			Number: 12,
+			Title:  "Feature request",
+		}, nil).Once()
+		clientMock.On("GetIssue", "test-org", "test-repo", 99999).Return(nil, errors.New("404 Not Found")).Once()
+
+		c := fixCompose(clientMock)
+		c.pullRequests = newPullRequestsCache()
+
+		pullRequests := c.listPullRequests(clientMock, "", "test-org", "test-repo", &github.CommitList{
+			Commits: []*go_github.RepositoryCommit{
+				{SHA: ptr.To("sha1")},
+				{SHA: ptr.To("sha2")},
+			},
+		})
+
+		want := []github.PullRequest{
+			{
+				Number: 123,
+				Title:  "Add new feature",
+				Issues: []github.Issue{
+					{Org: "test-org", Repo: "test-repo", Number: 12, Title: "Feature request"},
+				},
+			},
+		}
+		require.Equal(t, map[string][]github.PullRequest{
+			"sha1": want,
+			"sha2": want,
+		}, pullRequests)
+	})
+}
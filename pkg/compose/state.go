@@ -0,0 +1,199 @@
+package compose
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pPrecel/PKUP/internal/file"
+	"github.com/pPrecel/PKUP/pkg/config"
+)
+
+// state contains users and repos composed before the interruption
+// it's saved after every completed user and repo to allow the next run to pick up where the previous one left off
+type state struct {
+	mutex sync.Mutex
+	// empty path disables saving
+	path string
+
+	// state from the other period is ignored
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+	// state from the other config is ignored ( see configHash )
+	ConfigHash string `json:"configHash"`
+	// composed users by their usernames ( e.g. "pPrecel, pprecel-enterprise" )
+	Users map[string]*userState `json:"users"`
+}
+
+type userState struct {
+	// true when all artifacts and the report are saved
+	Done bool `json:"done"`
+	// repos with saved artifacts in format <ORG>/<REPO>
+	Repos []string `json:"repos,omitempty"`
+}
+
+// loadState reads the state saved in the previous run for the same period and config
+// returns empty state when the file does not exist
+func loadState(path string, since, until time.Time, configHash string) (*state, error) {
+	s := &state{
+		path:       path,
+		Since:      since,
+		Until:      until,
+		ConfigHash: configHash,
+		Users:      map[string]*userState{},
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %s", err.Error())
+	}
+
+	saved := &state{}
+	if err := json.Unmarshal(data, saved); err != nil {
+		return nil, fmt.Errorf("failed to decode state file '%s': %s", path, err.Error())
+	}
+
+	if !saved.Since.Equal(since) || !saved.Until.Equal(until) || saved.ConfigHash != configHash || saved.Users == nil {
+		// start over when period or config changed
+		return s, nil
+	}
+
+	s.Users = saved.Users
+	return s, nil
+}
+
+// configHash returns hash of the resolved config without secrets
+// tokens and send credentials are skipped so rotating them doesn't discard the state
+func configHash(cfg *config.Config) (string, error) {
+	c := *cfg
+	c.Orgs = orgsWithoutTokens(c.Orgs)
+	c.Repos = remotesWithoutTokens(c.Repos)
+	c.Reports = append([]config.Report{}, c.Reports...)
+	for i := range c.Reports {
+		c.Reports[i].Orgs = orgsWithoutTokens(c.Reports[i].Orgs)
+		c.Reports[i].Repos = remotesWithoutTokens(c.Reports[i].Repos)
+	}
+	c.Send.Username = ""
+	c.Send.Password = ""
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal config: %s", err.Error())
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func orgsWithoutTokens(orgs []config.Org) []config.Org {
+	orgs = append([]config.Org{}, orgs...)
+	for i := range orgs {
+		orgs[i].Token = ""
+	}
+
+	return orgs
+}
+
+func remotesWithoutTokens(remotes []config.Remote) []config.Remote {
+	remotes = append([]config.Remote{}, remotes...)
+	for i := range remotes {
+		remotes[i].Token = ""
+	}
+
+	return remotes
+}
+
+func (s *state) isUserDone(user string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.Users[user] != nil && s.Users[user].Done
+}
+
+func (s *state) isRepoDone(user, repo string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.Users[user] == nil {
+		return false
+	}
+
+	for _, r := range s.Users[user].Repos {
+		if r == repo {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *state) setRepoDone(user, repo string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.Users[user] == nil {
+		s.Users[user] = &userState{}
+	}
+
+	for _, r := range s.Users[user].Repos {
+		if r == repo {
+			return nil
+		}
+	}
+
+	s.Users[user].Repos = append(s.Users[user].Repos, repo)
+	return s.save()
+}
+
+func (s *state) setUserDone(user string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.Users[user] = &userState{Done: true}
+	return s.save()
+}
+
+// remove deletes the state file when everything is composed
+func (s *state) remove() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.path == "" {
+		return nil
+	}
+
+	err := os.Remove(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *state) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := file.WriteAtomic(s.path, data); err != nil {
+		return fmt.Errorf("failed to save state file: %s", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pPrecel/PKUP/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_state(t *testing.T) {
+	since := time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 2, 18, 23, 59, 59, 0, time.UTC)
+
+	t.Run("resume from saved state", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.json")
+
+		s, err := loadState(path, since, until, "hash")
+		require.NoError(t, err)
+		require.NoError(t, s.setRepoDone("user1", "org/repo1"))
+		require.NoError(t, s.setRepoDone("user1", "org/repo1"))
+		require.NoError(t, s.setUserDone("user2"))
+
+		resumed, err := loadState(path, since, until, "hash")
+		require.NoError(t, err)
+		require.True(t, resumed.isRepoDone("user1", "org/repo1"))
+		require.False(t, resumed.isRepoDone("user1", "org/repo2"))
+		require.False(t, resumed.isUserDone("user1"))
+		require.True(t, resumed.isUserDone("user2"))
+		require.Equal(t, []string{"org/repo1"}, resumed.Users["user1"].Repos)
+
+		require.NoError(t, resumed.remove())
+		require.NoFileExists(t, path)
+		require.NoError(t, resumed.remove())
+	})
+
+	t.Run("ignore state from other period", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.json")
+
+		s, err := loadState(path, since, until, "hash")
+		require.NoError(t, err)
+		require.NoError(t, s.setUserDone("user1"))
+
+		resumed, err := loadState(path, since.AddDate(0, 1, 0), until.AddDate(0, 1, 0), "hash")
+		require.NoError(t, err)
+		require.False(t, resumed.isUserDone("user1"))
+	})
+
+	t.Run("ignore state from other config", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.json")
+
+		s, err := loadState(path, since, until, "hash")
+		require.NoError(t, err)
+		require.NoError(t, s.setUserDone("user1"))
+
+		resumed, err := loadState(path, since, until, "other-hash")
+		require.NoError(t, err)
+		require.False(t, resumed.isUserDone("user1"))
+	})
+
+	t.Run("don't save without path", func(t *testing.T) {
+		s, err := loadState("", since, until, "hash")
+		require.NoError(t, err)
+		require.NoError(t, s.setUserDone("user1"))
+		require.True(t, s.isUserDone("user1"))
+		require.NoError(t, s.remove())
+	})
+
+	t.Run("broken state file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.json")
+		require.NoError(t, os.WriteFile(path, []byte("{"), 0644))
+
+		_, err := loadState(path, since, until, "hash")
+		require.Error(t, err)
+	})
+}
+
+func Test_configHash(t *testing.T) {
+	cfg := &config.Config{
+		Orgs: []config.Org{
+			{Remote: config.Remote{Name: "test-org", Token: "token1"}},
+		},
+		Reports: []config.Report{
+			{
+				OutputDir: "/out",
+				Repos:     []config.Remote{{Name: "test-org/test-repo", Token: "token1"}},
+			},
+		},
+		Send: config.Send{Password: "password1"},
+	}
+
+	hash, err := configHash(cfg)
+	require.NoError(t, err)
+
+	t.Run("skip secrets", func(t *testing.T) {
+		rotated := *cfg
+		rotated.Orgs = []config.Org{{Remote: config.Remote{Name: "test-org", Token: "token2"}}}
+		rotated.Reports = []config.Report{
+			{
+				OutputDir: "/out",
+				Repos:     []config.Remote{{Name: "test-org/test-repo", Token: "token2"}},
+			},
+		}
+		rotated.Send.Password = "password2"
+
+		got, err := configHash(&rotated)
+		require.NoError(t, err)
+		require.Equal(t, hash, got)
+
+		// the config is not modified
+		require.Equal(t, "token1", cfg.Orgs[0].Token)
+		require.Equal(t, "token1", cfg.Reports[0].Repos[0].Token)
+		require.Equal(t, "password1", cfg.Send.Password)
+	})
+
+	t.Run("changed config", func(t *testing.T) {
+		changed := *cfg
+		changed.Reports = []config.Report{
+			{
+				OutputDir: "/other",
+				Repos:     []config.Remote{{Name: "test-org/test-repo", Token: "token1"}},
+			},
+		}
+
+		got, err := configHash(&changed)
+		require.NoError(t, err)
+		require.NotEqual(t, hash, got)
+	})
+}
@@ -0,0 +1,97 @@
+package compose
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// the first failure stops composing for all users
+	FailurePolicyFailFast = "fail-fast"
+	// failed repos are skipped and the report is generated from other repos
+	FailurePolicySkipRepo = "skip-repo"
+	// any failure skips the user and other users are composed
+	FailurePolicySkipUser = "skip-user"
+)
+
+// list of all supported failure policies
+var FailurePolicies = []string{
+	FailurePolicyFailFast,
+	FailurePolicySkipRepo,
+	FailurePolicySkipUser,
+}
+
+func ValidateFailurePolicy(policy string) error {
+	if policy == "" {
+		return nil
+	}
+
+	for _, p := range FailurePolicies {
+		if p == policy {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported failure policy '%s' (supported: %s)", policy, strings.Join(FailurePolicies, ", "))
+}
+
+const (
+	// everything is composed
+	UserStatusSucceeded = "succeeded"
+	// some repos were skipped because of failures
+	UserStatusPartial = "partial"
+	// nothing is composed
+	UserStatusFailed = "failed"
+	// composed in the previous run
+	UserStatusSkipped = "skipped"
+)
+
+// Summary contains results of all users in order of reports from the config
+type Summary struct {
+	Users []UserSummary `json:"users"`
+	// number of requests sent to all GitHub APIs including retries
+	APICalls int64 `json:"apiCalls"`
+}
+
+type UserSummary struct {
+	// usernames from signatures ( e.g. "pPrecel, pprecel-enterprise" )
+	User string `json:"user"`
+	// one of UserStatus* values
+	Status string `json:"status"`
+	// number of commits in the report
+	Commits int `json:"commits"`
+	// absolute path of the user output dir
+	OutputDir string `json:"outputDir,omitempty"`
+	// paths of saved diffs ( planned ones in the dry run )
+	Artifacts []string `json:"artifacts,omitempty"`
+	// paths of rendered reports and the manifest ( planned ones in the dry run )
+	Reports []string `json:"reports,omitempty"`
+	// reasons of the failure or skipped repos
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Failed returns true when no user succeeded
+func (s *Summary) Failed() bool {
+	if len(s.Users) == 0 {
+		return false
+	}
+
+	for _, user := range s.Users {
+		if user.Status != UserStatusFailed {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Partial returns true when some users failed or have skipped repos
+func (s *Summary) Partial() bool {
+	for _, user := range s.Users {
+		if user.Status == UserStatusFailed || user.Status == UserStatusPartial {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,60 @@
+package compose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    []string
+		wantFailed  bool
+		wantPartial bool
+	}{
+		{
+			name:     "no users",
+			statuses: []string{},
+		},
+		{
+			name:     "all succeeded",
+			statuses: []string{UserStatusSucceeded, UserStatusSkipped},
+		},
+		{
+			name:        "some repos skipped",
+			statuses:    []string{UserStatusSucceeded, UserStatusPartial},
+			wantPartial: true,
+		},
+		{
+			name:        "some users failed",
+			statuses:    []string{UserStatusFailed, UserStatusSucceeded},
+			wantPartial: true,
+		},
+		{
+			name:        "all users failed",
+			statuses:    []string{UserStatusFailed, UserStatusFailed},
+			wantFailed:  true,
+			wantPartial: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := &Summary{}
+			for _, status := range tt.statuses {
+				summary.Users = append(summary.Users, UserSummary{Status: status})
+			}
+
+			require.Equal(t, tt.wantFailed, summary.Failed())
+			require.Equal(t, tt.wantPartial, summary.Partial())
+		})
+	}
+}
+
+func TestValidateFailurePolicy(t *testing.T) {
+	for _, policy := range append(FailurePolicies, "") {
+		require.NoError(t, ValidateFailurePolicy(policy))
+	}
+
+	require.ErrorContains(t, ValidateFailurePolicy("skip-all"), "unsupported failure policy 'skip-all'")
+}
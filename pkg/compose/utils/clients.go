@@ -24,47 +24,47 @@ func (rc RemoteClients) set(url string, client github.Client) {
 
 type BuildClientFunc func(context.Context, *pterm.Logger, github.ClientOpts) (github.Client, error)
 
-func BuildClients(ctx context.Context, logger *pterm.Logger, config *config.Config, buildClient BuildClientFunc) (*RemoteClients, error) {
-	remoteClients := &RemoteClients{}
-
-	err := appendRemoteClients(remoteClients, ctx, logger, orgsToRemotes(config.Orgs), buildClient)
-	if err != nil {
-		return nil, err
+// BuildClients builds one client for every enterprise url used by orgs and repos of the config and its reports
+// the client uses the first token set for the url ( config validation doesn't allow different tokens )
+func BuildClients(ctx context.Context, logger *pterm.Logger, cfg *config.Config, buildClient BuildClientFunc) (*RemoteClients, error) {
+	remotes := append(config.OrgsToRemotes(cfg.Orgs), cfg.Repos...)
+
+	// reports can use own orgs and repos
+	for _, report := range cfg.Reports {
+		remotes = append(remotes, config.OrgsToRemotes(report.Orgs)...)
+		remotes = append(remotes, report.Repos...)
 	}
 
-	err = appendRemoteClients(remoteClients, ctx, logger, config.Repos, buildClient)
-
-	return remoteClients, err
-}
-
-func appendRemoteClients(dest *RemoteClients, ctx context.Context, logger *pterm.Logger, remotes []config.Remote, buildClient BuildClientFunc) error {
+	remoteClients := &RemoteClients{}
 	for i := range remotes {
-		if c := dest.Get(remotes[i].EnterpriseUrl); c == nil {
-			var err error
-			client, err := buildClient(
-				ctx,
-				logger,
-				github.ClientOpts{
-					EnterpriseURL: remotes[i].EnterpriseUrl,
-					Token:         remotes[i].Token,
-				},
-			)
-			if err != nil {
-				return fmt.Errorf("failed to build client for '%s': %s", remotes[i].Name, err.Error())
-			}
+		if c := remoteClients.Get(remotes[i].EnterpriseUrl); c != nil {
+			continue
+		}
 
-			dest.set(remotes[i].EnterpriseUrl, client)
+		client, err := buildClient(
+			ctx,
+			logger,
+			github.ClientOpts{
+				EnterpriseURL: remotes[i].EnterpriseUrl,
+				Token:         remoteToken(remotes, remotes[i].EnterpriseUrl),
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build client for '%s': %s", remotes[i].Name, err.Error())
 		}
+
+		remoteClients.set(remotes[i].EnterpriseUrl, client)
 	}
 
-	return nil
+	return remoteClients, nil
 }
 
-func orgsToRemotes(orgs []config.Org) []config.Remote {
-	remotes := make([]config.Remote, len(orgs))
-	for i := range orgs {
-		remotes[i] = orgs[i].Remote
+func remoteToken(remotes []config.Remote, url string) string {
+	for i := range remotes {
+		if remotes[i].EnterpriseUrl == url && remotes[i].Token != "" {
+			return remotes[i].Token
+		}
 	}
 
-	return remotes
+	return ""
 }
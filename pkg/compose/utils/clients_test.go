@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pPrecel/PKUP/pkg/config"
+	"github.com/pPrecel/PKUP/pkg/github"
+	"github.com/pPrecel/PKUP/pkg/github/automock"
+	"github.com/pterm/pterm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildClients(t *testing.T) {
+	t.Run("build one client per url with token set by report", func(t *testing.T) {
+		cfg := &config.Config{
+			Orgs: []config.Org{
+				{Remote: config.Remote{Name: "org-a"}},
+			},
+			Repos: []config.Remote{
+				{Name: "org-b/repo-a", EnterpriseUrl: "https://github.enterprise/api/v3", Token: "enterprise-token"},
+			},
+			Reports: []config.Report{
+				{
+					Repos: []config.Remote{
+						{Name: "org-a/repo-b", Token: "report-token"},
+					},
+				},
+			},
+		}
+
+		built := []github.ClientOpts{}
+		clients, err := BuildClients(context.Background(), &pterm.DefaultLogger, cfg,
+			func(_ context.Context, _ *pterm.Logger, opts github.ClientOpts) (github.Client, error) {
+				built = append(built, opts)
+				return automock.NewClient(t), nil
+			})
+		require.NoError(t, err)
+		require.Equal(t, []github.ClientOpts{
+			{Token: "report-token"},
+			{EnterpriseURL: "https://github.enterprise/api/v3", Token: "enterprise-token"},
+		}, built)
+		require.NotNil(t, clients.Get(DefaultGitHubURL))
+		require.NotNil(t, clients.Get("https://github.enterprise/api/v3"))
+	})
+}
@@ -1,12 +1,12 @@
 package utils
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/hashicorp/go-multierror"
 	"github.com/pPrecel/PKUP/pkg/config"
 	"github.com/pPrecel/PKUP/pkg/github"
 	"github.com/pterm/pterm"
@@ -18,6 +18,16 @@ type LazyCommitsLister interface {
 
 type RepoCommitsList struct {
 	RepoCommits []RepoCommits
+	// repos without listed commits
+	Failed []RepoError
+}
+
+// RepoError describes why commits of the repo were not listed
+type RepoError struct {
+	Org           string
+	Repo          string
+	EnterpriseUrl string
+	Err           error
 }
 
 type RepoCommits struct {
@@ -28,29 +38,38 @@ type RepoCommits struct {
 }
 
 type lazyRepoCommitsLister struct {
-	mutex           sync.Mutex
-	repoCommitsList *RepoCommitsList
+	mutex sync.Mutex
+	// commits listed for orgs, repos and period
+	repoCommitsLists map[string]*RepoCommitsList
 
+	ctx           context.Context
 	remoteClients *RemoteClients
+	pool          *Pool
 	logger        *pterm.Logger
 }
 
-func NewLazyRepoCommitsLister(logger *pterm.Logger, remoteClients *RemoteClients) LazyCommitsLister {
+func NewLazyRepoCommitsLister(ctx context.Context, logger *pterm.Logger, remoteClients *RemoteClients, pool *Pool) LazyCommitsLister {
 	return &lazyRepoCommitsLister{
-		remoteClients: remoteClients,
-		logger:        logger,
+		ctx:              ctx,
+		repoCommitsLists: map[string]*RepoCommitsList{},
+		remoteClients:    remoteClients,
+		pool:             pool,
+		logger:           logger,
 	}
 }
 
 // list commits if were lister before
 // if not then list them from remote
+// commits are listed once for every set of orgs, repos and period ( reports can override them )
+// repos with failed listing are returned in the Failed list so every report can decide what to do with them
 func (ll *lazyRepoCommitsLister) List(config *config.Config, since, until time.Time) (*RepoCommitsList, error) {
 	ll.mutex.Lock()
 	defer ll.mutex.Unlock()
 
 	// return if commits were lister before
-	if ll.repoCommitsList != nil {
-		return ll.repoCommitsList, nil
+	key := commitsListKey(config, since, until)
+	if repoCommitsList, ok := ll.repoCommitsLists[key]; ok {
+		return repoCommitsList, nil
 	}
 
 	repos, err := ll.listOrgRepos(ll.remoteClients, config)
@@ -58,49 +77,84 @@ func (ll *lazyRepoCommitsLister) List(config *config.Config, since, until time.T
 		return nil, fmt.Errorf("failed to list repositories for orgs: %s", err.Error())
 	}
 
-	wg := sync.WaitGroup{}
-	allRepoCommits := []RepoCommits{}
-	for _, r := range repos {
-		repo := r
-
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-
-			orgName, repoName := SplitRemoteName(repo.Name)
-			client := ll.remoteClients.Get(repo.EnterpriseUrl)
-
-			ll.logger.Trace("listing commits for repo", ll.logger.Args("org", orgName, "repo", repoName))
-			commitList, listErr := client.ListRepoCommits(github.ListRepoCommitsOpts{
-				Org:        orgName,
-				Repo:       repoName,
-				Since:      since,
-				Until:      until,
-				Branches:   repo.Branches,
-				UniqueOnly: repo.UniqueOnly,
-			})
-			if listErr != nil {
-				ll.logger.Warn("failed to list commits", ll.logger.Args("org", orgName, "repo", repoName, "error", listErr.Error()))
-				err = multierror.Append(err, listErr)
-				return
-			}
-
-			ll.logger.Debug("found commits", ll.logger.Args("org", orgName, "repo", repoName, "count", len(commitList.Commits)))
-			allRepoCommits = append(allRepoCommits, RepoCommits{
+	// every job writes only to its own index so results don't depend on the jobs order
+	listed := make([]*RepoCommits, len(repos))
+	failed := make([]*RepoError, len(repos))
+	err = ll.pool.Run(ll.ctx, len(repos), func(i int) error {
+		repo := repos[i]
+		orgName, repoName := SplitRemoteName(repo.Name)
+		client := ll.remoteClients.Get(repo.EnterpriseUrl)
+
+		ll.logger.Trace("listing commits for repo", ll.logger.Args("org", orgName, "repo", repoName))
+		commitList, listErr := client.ListRepoCommits(github.ListRepoCommitsOpts{
+			Org:        orgName,
+			Repo:       repoName,
+			Since:      since,
+			Until:      until,
+			Branches:   repo.Branches,
+			UniqueOnly: repo.UniqueOnly,
+		})
+		if listErr != nil {
+			ll.logger.Warn("failed to list commits", ll.logger.Args("org", orgName, "repo", repoName, "error", listErr.Error()))
+			failed[i] = &RepoError{
 				Org:           orgName,
 				Repo:          repoName,
 				EnterpriseUrl: repo.EnterpriseUrl,
-				Commits:       commitList,
-			})
-		}()
+				Err:           listErr,
+			}
+			return nil
+		}
+
+		ll.logger.Debug("found commits", ll.logger.Args("org", orgName, "repo", repoName, "count", len(commitList.Commits)))
+		listed[i] = &RepoCommits{
+			Org:           orgName,
+			Repo:          repoName,
+			EnterpriseUrl: repo.EnterpriseUrl,
+			Commits:       commitList,
+		}
+		return nil
+	})
+
+	if err != nil {
+		// listing was canceled
+		return nil, err
 	}
 
-	wg.Wait()
+	repoCommitsList := &RepoCommitsList{
+		RepoCommits: []RepoCommits{},
+		Failed:      []RepoError{},
+	}
+	for i := range repos {
+		if listed[i] != nil {
+			repoCommitsList.RepoCommits = append(repoCommitsList.RepoCommits, *listed[i])
+		}
 
-	ll.repoCommitsList = &RepoCommitsList{
-		RepoCommits: allRepoCommits,
+		if failed[i] != nil {
+			repoCommitsList.Failed = append(repoCommitsList.Failed, *failed[i])
+		}
 	}
-	return ll.repoCommitsList, err
+	SortRepoCommits(repoCommitsList.RepoCommits)
+
+	ll.repoCommitsLists[key] = repoCommitsList
+	return repoCommitsList, nil
+}
+
+// commitsListKey identifies listed commits by the orgs and repos options and the period
+// tokens are not part of the key ( they don't change results and should not be kept in memory longer than needed )
+func commitsListKey(config *config.Config, since, until time.Time) string {
+	remotes := []string{}
+	for _, org := range config.Orgs {
+		remotes = append(remotes, fmt.Sprintf("%s|ignore=%v", remoteKey(org.Remote), org.IgnoreRepos))
+	}
+	for _, repo := range config.Repos {
+		remotes = append(remotes, remoteKey(repo))
+	}
+
+	return fmt.Sprintf("%s|%d|%d", strings.Join(remotes, ";"), since.Unix(), until.Unix())
+}
+
+func remoteKey(remote config.Remote) string {
+	return fmt.Sprintf("%s|%s|%v|%t|%t", remote.Name, remote.EnterpriseUrl, remote.Branches, remote.AllBranches, remote.UniqueOnly)
 }
 
 func (ll *lazyRepoCommitsLister) listOrgRepos(remoteClients *RemoteClients, cfg *config.Config) ([]config.Remote, error) {
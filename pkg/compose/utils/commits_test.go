@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	go_github "github.com/google/go-github/v53/github"
+	"github.com/pPrecel/PKUP/pkg/config"
+	"github.com/pPrecel/PKUP/pkg/github"
+	"github.com/pPrecel/PKUP/pkg/github/automock"
+	"github.com/pterm/pterm"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyRepoCommitsLister_List(t *testing.T) {
+	now := time.Now()
+	cfg := &config.Config{
+		Orgs: []config.Org{
+			{Remote: config.Remote{Name: "org-b"}},
+		},
+		Repos: []config.Remote{
+			{Name: "org-a/repo-b"},
+			{Name: "org-a/repo-a"},
+		},
+	}
+
+	t.Run("list sorted commits once", func(t *testing.T) {
+		clientMock := automock.NewClient(t)
+		clientMock.On("ListRepos", "org-b").Return([]string{"repo-b", "repo-a"}, nil).Once()
+		clientMock.On("ListRepoCommits", mock.Anything).Return(func(opts github.ListRepoCommitsOpts) (*github.CommitList, error) {
+			return &github.CommitList{
+				Commits: []*go_github.RepositoryCommit{
+					fixCommit(opts.Repo+"-2", now),
+					fixCommit(opts.Repo+"-1", now.Add(-time.Hour)),
+				},
+			}, nil
+		}).Times(4)
+
+		lister := NewLazyRepoCommitsLister(context.Background(), &pterm.DefaultLogger, &RemoteClients{DefaultGitHubURL: clientMock}, NewPool(2))
+
+		repoCommitsList, err := lister.List(cfg, now.Add(-time.Hour*24), now)
+		require.NoError(t, err)
+
+		names := []string{}
+		for _, repo := range repoCommitsList.RepoCommits {
+			names = append(names, repo.Org+"/"+repo.Repo)
+			require.Equal(t, repo.Repo+"-1", repo.Commits.Commits[0].GetSHA())
+		}
+		require.Equal(t, []string{"org-a/repo-a", "org-a/repo-b", "org-b/repo-a", "org-b/repo-b"}, names)
+
+		// second call returns cached commits
+		cached, err := lister.List(cfg, now.Add(-time.Hour*24), now)
+		require.NoError(t, err)
+		require.Equal(t, repoCommitsList, cached)
+	})
+
+	t.Run("return listed commits and failed repos", func(t *testing.T) {
+		clientMock := automock.NewClient(t)
+		clientMock.On("ListRepos", "org-b").Return([]string{}, nil).Once()
+		clientMock.On("ListRepoCommits", mock.MatchedBy(func(opts github.ListRepoCommitsOpts) bool {
+			return opts.Repo == "repo-a"
+		})).Return(&github.CommitList{}, nil).Once()
+		clientMock.On("ListRepoCommits", mock.MatchedBy(func(opts github.ListRepoCommitsOpts) bool {
+			return opts.Repo == "repo-b"
+		})).Return(nil, errors.New("test error")).Once()
+
+		lister := NewLazyRepoCommitsLister(context.Background(), &pterm.DefaultLogger, &RemoteClients{DefaultGitHubURL: clientMock}, NewPool(1))
+
+		repoCommitsList, err := lister.List(cfg, now.Add(-time.Hour*24), now)
+		require.NoError(t, err)
+		require.Len(t, repoCommitsList.RepoCommits, 1)
+		require.Equal(t, "repo-a", repoCommitsList.RepoCommits[0].Repo)
+		require.Equal(t, []RepoError{
+			{Org: "org-a", Repo: "repo-b", Err: errors.New("test error")},
+		}, repoCommitsList.Failed)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		clientMock := automock.NewClient(t)
+		clientMock.On("ListRepos", "org-b").Return([]string{}, nil).Once()
+
+		lister := NewLazyRepoCommitsLister(ctx, &pterm.DefaultLogger, &RemoteClients{DefaultGitHubURL: clientMock}, NewPool(1))
+
+		repoCommitsList, err := lister.List(cfg, now.Add(-time.Hour*24), now)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Nil(t, repoCommitsList)
+	})
+}
+
+func Test_commitsListKey(t *testing.T) {
+	since := time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC)
+	fixConfig := func(token string, branches []string) *config.Config {
+		return &config.Config{
+			Orgs: []config.Org{
+				{Remote: config.Remote{Name: "org-b", Token: token}, IgnoreRepos: []string{"repo-c"}},
+			},
+			Repos: []config.Remote{
+				{Name: "org-a/repo-a", EnterpriseUrl: "https://github.enterprise/api/v3", Token: token, Branches: branches},
+			},
+		}
+	}
+
+	t.Run("skip tokens", func(t *testing.T) {
+		key := commitsListKey(fixConfig("secret-token-1", nil), since, until)
+		require.Equal(t, key, commitsListKey(fixConfig("secret-token-2", nil), since, until))
+		require.NotContains(t, key, "secret-token")
+	})
+
+	t.Run("different branches", func(t *testing.T) {
+		require.NotEqual(t,
+			commitsListKey(fixConfig("", nil), since, until),
+			commitsListKey(fixConfig("", []string{"release-1.0"}), since, until),
+		)
+	})
+
+	t.Run("different period", func(t *testing.T) {
+		require.NotEqual(t,
+			commitsListKey(fixConfig("", nil), since, until),
+			commitsListKey(fixConfig("", nil), since, until.Add(time.Hour)),
+		)
+	})
+}
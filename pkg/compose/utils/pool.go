@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// number of concurrent jobs used when parallelism is not set
+const DefaultParallelism = 8
+
+// Pool limits number of concurrently running jobs
+// one pool can be shared between many callers to limit all jobs together
+type Pool struct {
+	sem chan struct{}
+}
+
+func NewPool(parallelism int) *Pool {
+	if parallelism < 1 {
+		parallelism = DefaultParallelism
+	}
+
+	return &Pool{
+		sem: make(chan struct{}, parallelism),
+	}
+}
+
+// Run calls fn for every index from 0 to count-1 and waits for all calls
+// errors are returned in order of indexes so the output does not depend on the jobs order
+// no more jobs are started after the ctx is canceled and the ctx error is returned
+func (p *Pool) Run(ctx context.Context, count int, fn func(int) error) error {
+	errs := make([]error, count)
+	wg := sync.WaitGroup{}
+	for i := 0; i < count; i++ {
+		acquired := false
+		select {
+		case <-ctx.Done():
+		case p.sem <- struct{}{}:
+			acquired = true
+		}
+
+		if ctx.Err() != nil {
+			// the slot can be taken when both cases were ready
+			if acquired {
+				<-p.sem
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-p.sem
+				wg.Done()
+			}()
+
+			errs[i] = fn(i)
+		}(i)
+	}
+
+	wg.Wait()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	var err error
+	for i := range errs {
+		if errs[i] != nil {
+			err = multierror.Append(err, errs[i])
+		}
+	}
+
+	return err
+}
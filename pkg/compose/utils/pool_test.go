@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool_Run(t *testing.T) {
+	t.Run("limit concurrent jobs", func(t *testing.T) {
+		pool := NewPool(2)
+
+		mutex := sync.Mutex{}
+		running, maxRunning := 0, 0
+		results := make([]int, 10)
+		err := pool.Run(context.Background(), len(results), func(i int) error {
+			mutex.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mutex.Unlock()
+
+			time.Sleep(time.Millisecond)
+			results[i] = i * 2
+
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+			return nil
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, 2, maxRunning)
+		require.Equal(t, []int{0, 2, 4, 6, 8, 10, 12, 14, 16, 18}, results)
+	})
+
+	t.Run("errors in order of indexes", func(t *testing.T) {
+		pool := NewPool(0)
+
+		err := pool.Run(context.Background(), 3, func(i int) error {
+			if i == 1 {
+				return nil
+			}
+
+			// later jobs finish first
+			time.Sleep(time.Duration(3-i) * time.Millisecond)
+			return errors.New(string(rune('a' + i)))
+		})
+
+		multiErr := &multierror.Error{}
+		require.ErrorAs(t, err, &multiErr)
+		require.Equal(t, []error{errors.New("a"), errors.New("c")}, multiErr.Errors)
+	})
+
+	t.Run("stop starting jobs when canceled", func(t *testing.T) {
+		pool := NewPool(1)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		started := 0
+		err := pool.Run(ctx, 10, func(i int) error {
+			started++
+			if i == 1 {
+				cancel()
+			}
+
+			return nil
+		})
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.Less(t, started, 10)
+	})
+
+	t.Run("no jobs", func(t *testing.T) {
+		require.NoError(t, NewPool(1).Run(context.Background(), 0, func(_ int) error {
+			return errors.New("should not be called")
+		}))
+	})
+}
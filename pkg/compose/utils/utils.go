@@ -1,8 +1,47 @@
 package utils
 
-import "strings"
+import (
+	"sort"
+	"strings"
+
+	go_github "github.com/google/go-github/v53/github"
+)
 
 func SplitRemoteName(remote string) (string, string) {
 	repoOrg := strings.Split(remote, "/")
 	return repoOrg[0], repoOrg[1]
 }
+
+// SortRepoCommits sorts repos by org and name and commits of every repo by the author date
+// commits with the same date are sorted by SHA to keep the output the same between runs
+func SortRepoCommits(repoCommits []RepoCommits) {
+	sort.SliceStable(repoCommits, func(i, j int) bool {
+		if repoCommits[i].Org != repoCommits[j].Org {
+			return repoCommits[i].Org < repoCommits[j].Org
+		}
+
+		if repoCommits[i].Repo != repoCommits[j].Repo {
+			return repoCommits[i].Repo < repoCommits[j].Repo
+		}
+
+		return repoCommits[i].EnterpriseUrl < repoCommits[j].EnterpriseUrl
+	})
+
+	for i := range repoCommits {
+		if repoCommits[i].Commits != nil {
+			sortCommits(repoCommits[i].Commits.Commits)
+		}
+	}
+}
+
+func sortCommits(commits []*go_github.RepositoryCommit) {
+	sort.SliceStable(commits, func(i, j int) bool {
+		iDate := commits[i].GetCommit().GetAuthor().GetDate().Time
+		jDate := commits[j].GetCommit().GetAuthor().GetDate().Time
+		if !iDate.Equal(jDate) {
+			return iDate.Before(jDate)
+		}
+
+		return commits[i].GetSHA() < commits[j].GetSHA()
+	})
+}
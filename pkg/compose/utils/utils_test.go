@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	go_github "github.com/google/go-github/v53/github"
+	"github.com/pPrecel/PKUP/pkg/github"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func TestSortRepoCommits(t *testing.T) {
+	now := time.Now()
+	repoCommits := []RepoCommits{
+		{Org: "org-b", Repo: "repo-a", Commits: &github.CommitList{}},
+		{Org: "org-a", Repo: "repo-b", Commits: &github.CommitList{}},
+		{Org: "org-a", Repo: "repo-a", Commits: &github.CommitList{
+			Commits: []*go_github.RepositoryCommit{
+				fixCommit("3", now.Add(time.Hour)),
+				fixCommit("2", now),
+				fixCommit("1", now),
+			},
+		}},
+	}
+
+	SortRepoCommits(repoCommits)
+
+	names := []string{}
+	for _, repo := range repoCommits {
+		names = append(names, repo.Org+"/"+repo.Repo)
+	}
+	require.Equal(t, []string{"org-a/repo-a", "org-a/repo-b", "org-b/repo-a"}, names)
+
+	shas := []string{}
+	for _, commit := range repoCommits[0].Commits.Commits {
+		shas = append(shas, commit.GetSHA())
+	}
+	require.Equal(t, []string{"1", "2", "3"}, shas)
+}
+
+func fixCommit(sha string, date time.Time) *go_github.RepositoryCommit {
+	return &go_github.RepositoryCommit{
+		SHA: ptr.To(sha),
+		Commit: &go_github.Commit{
+			Author: &go_github.CommitAuthor{
+				Date: &go_github.Timestamp{Time: date},
+			},
+		},
+	}
+}
@@ -1,15 +1,23 @@
 package config
 
 import (
-	"os"
+	"fmt"
 	"time"
 
-	"gopkg.in/yaml.v3"
+	"github.com/pPrecel/PKUP/pkg/report"
 )
 
 type Config struct {
-	// path to the report template
+	// paths to other config files merged before this file ( relative to the including file )
+	// lists are appended ( orgs and repos with the same name and enterpriseUrl are replaced )
+	// and values set in the including file override included ones
+	// relative paths set in included files ( e.g. template or outputDir ) are relative to the included file
+	// e.g.: ["shared/orgs.yaml", "shared/send.yaml"]
+	Include []string `yaml:"include,omitempty"`
+	// path to the report template ( .docx, .odt or go text template .tmpl/.txt/.md )
 	Template string `yaml:"template"`
+	// path to the yaml file describing layout of the pdf report ( default: built-in layout )
+	PDFLayout string `yaml:"pdfLayout,omitempty"`
 	// repos based on which report will be generated ( with name in format <ORG>/<REPO> )
 	// can override orgs config for a specific repo
 	Repos []Remote `yaml:"repos,omitempty"`
@@ -19,6 +27,43 @@ type Config struct {
 	Reports []Report `yaml:"reports,omitempty"`
 	// info about email server used to send emails
 	Send Send `yaml:"send,omitempty"`
+	// handling of commits created by bot accounts ( e.g. dependabot commits co-authored by the user )
+	Bots Bots `yaml:"bots,omitempty"`
+	// rules excluding commits from all reports ( commit is excluded when all conditions of any rule match )
+	Rules []Rule `yaml:"rules,omitempty"`
+}
+
+type Bots struct {
+	// what to do with bot commits matching the user ( default: "include" )
+	// available modes: "include", "drop", "flag" ( include and mark with the "[bot]" prefix in reports )
+	Mode string `yaml:"mode,omitempty"`
+	// additional logins or names of bot accounts ( accounts with the Bot type or the "[bot]" suffix are always detected )
+	// e.g.: ["kyma-bot", "internal-automation"]
+	Logins []string `yaml:"logins,omitempty"`
+}
+
+type Rule struct {
+	// name displayed next to the number of excluded commits ( default: "rule #<index>" )
+	// e.g.: "dependency bumps"
+	Name string `yaml:"name,omitempty"`
+	// regular expression matching the whole commit message
+	// e.g.: "^(chore|build)\\(deps\\)"
+	Message string `yaml:"message,omitempty"`
+	// regular expressions matching paths of changed files - commit is excluded when all changed files match
+	// e.g.: ["^docs/", "\\.md$"]
+	Paths []string `yaml:"paths,omitempty"`
+	// exclude commits created by bot accounts ( default: false )
+	BotAuthor bool `yaml:"botAuthor,omitempty"`
+	// exclude merge commits ( default: false )
+	Merge bool `yaml:"merge,omitempty"`
+	// exclude revert commits together with reverted commits found in the same repo ( default: false )
+	RevertPairs bool `yaml:"revertPairs,omitempty"`
+	// exclude commits with less changed lines ( additions plus deletions )
+	// e.g.: 3
+	MinLines int `yaml:"minLines,omitempty"`
+	// exclude commits with more changed lines ( additions plus deletions )
+	// e.g.: 5000
+	MaxLines int `yaml:"maxLines,omitempty"`
 }
 
 type Send struct {
@@ -30,11 +75,13 @@ type Send struct {
 	ServerPort int `yaml:"serverPort"`
 	// email server username
 	// e.g.: filip.strozik@outlook.com
+	// supports ${ENV} variables and references: "env:<NAME>", "file:<PATH>", "keyring:<SERVICE>/<USER>", "cmd:<COMMAND>"
 	Username string `yaml:"username"`
 	// email server password
 	// how to create app password for gmail:
 	// https://support.google.com/accounts/answer/185833?hl=en
-	// e.g.: testpassword
+	// e.g.: testpassword, "env:SMTP_PASSWORD" or "cmd:pass show pkup/smtp"
+	// supports ${ENV} variables and references: "env:<NAME>", "file:<PATH>", "keyring:<SERVICE>/<USER>", "cmd:<COMMAND>"
 	Password string `yaml:"password"`
 	// how many emails should be send on single dial ( default: 1 )
 	// gmail smtp server limitations:
@@ -67,6 +114,8 @@ type Remote struct {
 	// e.g.: "kyma-project" or "kyma-project/serverless"
 	Name string `yaml:"name"`
 	// token used to communicate with the GitHub API
+	// e.g.: "${GITHUB_TOKEN}" or "keyring:pkup/github-token"
+	// supports ${ENV} variables and references: "env:<NAME>", "file:<PATH>", "keyring:<SERVICE>/<USER>", "cmd:<COMMAND>"
 	Token string `yaml:"token,omitempty"`
 	// enterprise GitHub API address ( default: use opensource GitHub API address )
 	EnterpriseUrl string `yaml:"enterpriseUrl,omitempty"`
@@ -89,6 +138,54 @@ type Report struct {
 	// extra fields that will be replaces in the template report
 	// e.g.: pkupGenEmployeesName: "Filip Strózik"
 	ExtraFields map[string]string `yaml:"extraFields,omitempty"`
+	// report formats generated next to the template report ( default: none )
+	// available formats: "txt", "md", "html", "pdf", "json", "yaml"
+	// e.g.: ["md", "html"]
+	Formats []string `yaml:"formats,omitempty"`
+	// split report results into sections ( default: flat list of commits )
+	// available options: "repo", "pr", "issue", "scope"
+	// e.g.: "pr"
+	GroupBy string `yaml:"groupBy,omitempty"`
+	// languages of the report ( default: legacy english labels and dates in format dd.mm.yyyy )
+	// every language variant is saved with the locale suffix when more than one locale is set
+	// available locales: "pl", "en"
+	// e.g.: ["pl", "en"]
+	Locales []string `yaml:"locales,omitempty"`
+	// fetch pull requests associated with commits and titles of issues closed by them ( default: false )
+	// data is available in text templates and used by the "pr" and "issue" groupBy options
+	// requires additional GitHub API calls for every commit
+	Enrich bool `yaml:"enrich,omitempty"`
+	// checks of rendered reports looking for unreplaced placeholders and unused extra fields
+	Validation Validation `yaml:"validation,omitempty"`
+	// path to the report template overriding the global template
+	// e.g.: "templates/report-pl.docx"
+	Template string `yaml:"template,omitempty"`
+	// orgs used instead of the global orgs and repos ( default: use global orgs and repos )
+	// repos and orgs of all reports with the same enterpriseUrl share one GitHub client ( their tokens must be the same )
+	Orgs []Org `yaml:"orgs,omitempty"`
+	// repos used instead of the global orgs and repos ( default: use global orgs and repos )
+	Repos []Remote `yaml:"repos,omitempty"`
+	// period of the report overriding the period passed to the command
+	Period Period `yaml:"period,omitempty"`
+}
+
+type Period struct {
+	// first day of the period in format dd.mm.yyyy
+	// e.g.: "19.09.2023"
+	Since string `yaml:"since,omitempty"`
+	// last day of the period ( included ) in format dd.mm.yyyy
+	// e.g.: "18.10.2023"
+	Until string `yaml:"until,omitempty"`
+}
+
+type Validation struct {
+	// what to do when rendered report contains issues ( default: "warn" )
+	// available modes: "fail", "warn", "off"
+	Mode string `yaml:"mode,omitempty"`
+	// additional regular expressions matching placeholders that should not stay in the report
+	// every word starting with 'pkupGen' is always reported
+	// e.g.: ["\\{\\{.*\\}\\}", "TODO"]
+	Patterns []string `yaml:"patterns,omitempty"`
 }
 
 type Signature struct {
@@ -100,13 +197,62 @@ type Signature struct {
 	EnterpriseUrl string `yaml:"enterpriseUrl,omitempty"`
 }
 
-func Read(path string) (*Config, error) {
-	yamlFile, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
+// ForReport returns copy of the config with the template and sources overridden by the report
+func (c *Config) ForReport(report *Report) *Config {
+	config := *c
+	if report.Template != "" {
+		config.Template = report.Template
 	}
 
-	config := &Config{}
+	if len(report.Orgs) > 0 || len(report.Repos) > 0 {
+		config.Orgs = report.Orgs
+		config.Repos = report.Repos
+	}
 
-	return config, yaml.Unmarshal(yamlFile, config)
+	return &config
+}
+
+// OrgsToRemotes returns remotes of the given orgs
+func OrgsToRemotes(orgs []Org) []Remote {
+	remotes := make([]Remote, len(orgs))
+	for i := range orgs {
+		remotes[i] = orgs[i].Remote
+	}
+
+	return remotes
+}
+
+// Bounds returns the report period or the given one if not overridden
+// the until day is included in the period
+func (p Period) Bounds(since, until time.Time) (time.Time, time.Time, error) {
+	if p.Since != "" {
+		t, err := time.ParseInLocation(report.PeriodFormat, p.Since, time.Local)
+		if err != nil {
+			return since, until, fmt.Errorf("failed to parse period since '%s': %s", p.Since, err.Error())
+		}
+
+		since = t
+	}
+
+	if p.Until != "" {
+		t, err := time.ParseInLocation(report.PeriodFormat, p.Until, time.Local)
+		if err != nil {
+			return since, until, fmt.Errorf("failed to parse period until '%s': %s", p.Until, err.Error())
+		}
+
+		until = t.Add(time.Hour*24 - time.Second)
+	}
+
+	if until.Before(since) {
+		return since, until, fmt.Errorf("period until '%s' is before since '%s'", until.Format(report.PeriodFormat), since.Format(report.PeriodFormat))
+	}
+
+	return since, until, nil
+}
+
+// Read decodes config rejecting unknown fields and validates its values
+// returns the *ValidationError with positions of all found issues
+// sensitive fields ( tokens and the send credentials ) are resolved from secret references
+func Read(path string) (*Config, error) {
+	return load(path)
 }
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	tmpDir := t.TempDir()
+	templatePath := path.Join(tmpDir, "template.docx")
+	require.NoError(t, os.WriteFile(templatePath, []byte{}, os.ModePerm))
+
+	tests := []struct {
+		name       string
+		config     string
+		wantIssues []Issue
+		wantErr    bool
+	}{
+		{
+			name: "valid config",
+			config: `
+template: ` + templatePath + `
+orgs:
+- name: kyma-project
+repos:
+- name: pPrecel/PKUP
+  enterpriseUrl: https://github.tools.sap/api/v3
+reports:
+- signatures:
+  - username: pPrecel
+  - username: pPrecel
+    enterpriseUrl: https://github.tools.sap/api/v3
+  email: test@test.com
+  formats: [md]
+send:
+  serverAddress: smtp.gmail.com
+`,
+		},
+		{
+			name: "unknown fields",
+			config: `
+repos:
+- name: pPrecel/PKUP
+  enterpriseURL: https://github.tools.sap/api/v3
+  unknown: true
+reports:
+- signatures:
+  - username: pPrecel
+  extraFields:
+    pkupGenAnyField: value
+`,
+			wantIssues: []Issue{
+				{Line: 4, Column: 3, Field: "repos[0]", Message: "unknown field 'enterpriseURL' (did you mean 'enterpriseUrl'?)"},
+				{Line: 5, Column: 3, Field: "repos[0]", Message: "unknown field 'unknown'"},
+			},
+		},
+		{
+			name: "wrong type",
+			config: `
+send:
+  serverPort: abc
+`,
+			wantIssues: []Issue{
+				{Line: 3, Column: 15, Message: "cannot unmarshal !!str `abc` into int"},
+			},
+		},
+		{
+			name: "semantic issues",
+			config: `
+template: ` + path.Join(tmpDir, "missing.docx") + `
+orgs:
+- name: kyma-project/cli
+repos:
+- name: pPrecel
+reports:
+- signatures:
+  - username: pPrecel
+    enterpriseUrl: https://github.tools.sap/api/v3
+  formats: [md, doc]
+send:
+  serverAddress: smtp.gmail.com
+`,
+			wantIssues: []Issue{
+				{Line: 2, Column: 11, Field: "template", Message: "template file '" + path.Join(tmpDir, "missing.docx") + "' does not exist"},
+				{Line: 4, Column: 9, Field: "orgs[0].name", Message: "org name 'kyma-project/cli' is not in format <ORG>"},
+				{Line: 6, Column: 9, Field: "repos[0].name", Message: "repo name 'pPrecel' is not in format <ORG>/<REPO>"},
+				{Line: 9, Column: 5, Field: "reports[0].signatures[0]", Message: "signature 'pPrecel' does not match any org or repo with the enterpriseUrl 'https://github.tools.sap/api/v3'"},
+				{Line: 8, Column: 3, Field: "reports[0]", Message: "email is required when the send config is set"},
+				{Line: 11, Column: 17, Field: "reports[0].formats[1]", Message: "unsupported report format 'doc' (supported: txt, md, html, pdf, json, yaml)"},
+			},
+		},
+		{
+			name: "unresolved secrets",
+			config: `
+repos:
+- name: pPrecel/PKUP
+  token: env:PKUP_TEST_MISSING
+reports:
+- signatures:
+  - username: pPrecel
+`,
+			wantIssues: []Issue{
+				{Line: 4, Column: 10, Field: "repos[0].token", Message: "environment variable 'PKUP_TEST_MISSING' is not set"},
+			},
+		},
+		{
+			name:    "syntax error",
+			config:  "reports: [",
+			wantErr: true,
+		},
+	}
+	t.Run("resolve secrets", func(t *testing.T) {
+		t.Setenv("PKUP_TEST_TOKEN", "test-token")
+		configPath := path.Join(tmpDir, "secrets.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte(`
+orgs:
+- name: kyma-project
+  token: ${PKUP_TEST_TOKEN}
+send:
+  password: env:PKUP_TEST_TOKEN
+`), os.ModePerm))
+
+		config, err := Read(configPath)
+		require.NoError(t, err)
+		require.Equal(t, "test-token", config.Orgs[0].Token)
+		require.Equal(t, "test-token", config.Send.Password)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := path.Join(tmpDir, "config.yaml")
+			require.NoError(t, os.WriteFile(configPath, []byte(tt.config), os.ModePerm))
+
+			config, err := Read(configPath)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Nil(t, config)
+				return
+			}
+
+			if len(tt.wantIssues) == 0 {
+				require.NoError(t, err)
+				require.NotNil(t, config)
+				return
+			}
+
+			for i := range tt.wantIssues {
+				tt.wantIssues[i].File = configPath
+			}
+
+			require.Nil(t, config)
+			validationErr, ok := err.(*ValidationError)
+			require.True(t, ok, err)
+			require.Equal(t, tt.wantIssues, validationErr.Issues)
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := &Config{
+		Orgs: []Org{
+			{Remote: Remote{Name: "kyma-project", Token: "${GITHUB_TOKEN}"}},
+			{Remote: Remote{Name: "kyma-incubator"}},
+		},
+		Reports: []Report{
+			{
+				Signatures: []Signature{{Username: "pPrecel"}},
+				OutputDir:  "reports/pPrecel",
+				Formats:    []string{"txt"},
+			},
+		},
+	}
+
+	data, err := Marshal(config, "test header")
+	require.NoError(t, err)
+	require.Contains(t, string(data), "# test header\n\n")
+	require.Contains(t, string(data), "# orgs based on which report will be generated ( with name in format <ORG> )\norgs:\n")
+	require.Contains(t, string(data), "    # token used to communicate with the GitHub API\n")
+	require.Equal(t, 1, strings.Count(string(data), "# token used to communicate with the GitHub API"))
+
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	configPath := path.Join(tmpDir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, data, os.ModePerm))
+
+	readConfig, err := Read(configPath)
+	require.NoError(t, err)
+	config.Orgs[0].Token = "test-token"
+	require.Equal(t, config, readConfig)
+}
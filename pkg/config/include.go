@@ -0,0 +1,258 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// source is a single decoded config file
+type source struct {
+	path   string
+	root   *yaml.Node
+	config *Config
+	issues []Issue
+	// true when some values can't be decoded
+	invalid bool
+}
+
+// origin is the file and the yaml node where the merged value is defined
+type origin struct {
+	path string
+	node *yaml.Node
+}
+
+// origins of merged values by the field path
+// e.g.: "template", "orgs[2]", "send.password"
+type origins map[string]origin
+
+func (o origins) set(field string, s *source, path ...interface{}) {
+	o[field] = origin{
+		path: s.path,
+		node: findNode(documentContent(s.root), path...),
+	}
+}
+
+// readSources returns the config file and all included files in the merge order
+// included files are merged before the including file and every file is read once
+func readSources(path string, loaded map[string]bool, including []string) ([]*source, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range including {
+		if p == path {
+			return nil, fmt.Errorf("include cycle detected: %s", strings.Join(append(including, path), " -> "))
+		}
+	}
+
+	if loaded[path] {
+		return nil, nil
+	}
+	loaded[path] = true
+
+	s, err := decodeSource(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(including) > 0 {
+		rebasePaths(s.config, filepath.Dir(path))
+	}
+
+	sources := []*source{}
+	for _, include := range s.config.Include {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+
+		included, err := readSources(include, loaded, append(including, path))
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, included...)
+	}
+
+	return append(sources, s), nil
+}
+
+// decodeSource decodes the config file collecting unknown fields and wrong types
+func decodeSource(path string) (*source, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal(data, root); err != nil {
+		return nil, fmt.Errorf("failed to parse '%s': %s", path, err.Error())
+	}
+
+	s := &source{
+		path:   path,
+		root:   root,
+		config: &Config{},
+	}
+
+	s.issues = findUnknownFields(root, reflect.TypeOf(s.config), "")
+	if err := root.Decode(s.config); err != nil {
+		typeErr, ok := err.(*yaml.TypeError)
+		if !ok {
+			return nil, fmt.Errorf("failed to decode '%s': %s", path, err.Error())
+		}
+
+		s.issues = append(s.issues, toTypeIssues(root, typeErr)...)
+		s.invalid = true
+	}
+
+	for i := range s.issues {
+		s.issues[i].File = path
+	}
+
+	return s, nil
+}
+
+// rebasePaths makes relative paths of the included file relative to its directory
+// paths of the main file are left relative to the working directory
+func rebasePaths(config *Config, dir string) {
+	rebase := func(path *string) {
+		if *path != "" && !filepath.IsAbs(*path) {
+			*path = filepath.Join(dir, *path)
+		}
+	}
+
+	rebase(&config.Template)
+	rebase(&config.PDFLayout)
+	rebase(&config.Send.HTMLBodyPath)
+	for i := range config.Reports {
+		rebase(&config.Reports[i].Template)
+		rebase(&config.Reports[i].OutputDir)
+	}
+}
+
+// merge returns config built from all sources in order
+// values set in later sources override earlier ones, lists are appended
+// orgs and repos with the same name and enterpriseUrl are replaced in place
+func merge(sources []*source) (*Config, origins) {
+	config := &Config{}
+	o := origins{}
+	for _, s := range sources {
+		// issues not related to any field are reported for the main file
+		o.set("", s)
+
+		c := s.config
+		if c.Template != "" {
+			config.Template = c.Template
+			o.set("template", s, "template")
+		}
+
+		if c.PDFLayout != "" {
+			config.PDFLayout = c.PDFLayout
+			o.set("pdfLayout", s, "pdfLayout")
+		}
+
+		for i := range c.Orgs {
+			index := indexOfRemote(OrgsToRemotes(config.Orgs), c.Orgs[i].Remote)
+			if index < 0 {
+				index = len(config.Orgs)
+				config.Orgs = append(config.Orgs, Org{})
+			}
+
+			config.Orgs[index] = c.Orgs[i]
+			o.set(fmt.Sprintf("orgs[%d]", index), s, "orgs", i)
+		}
+
+		for i := range c.Repos {
+			index := indexOfRemote(config.Repos, c.Repos[i])
+			if index < 0 {
+				index = len(config.Repos)
+				config.Repos = append(config.Repos, Remote{})
+			}
+
+			config.Repos[index] = c.Repos[i]
+			o.set(fmt.Sprintf("repos[%d]", index), s, "repos", i)
+		}
+
+		for i := range c.Reports {
+			o.set(fmt.Sprintf("reports[%d]", len(config.Reports)), s, "reports", i)
+			config.Reports = append(config.Reports, c.Reports[i])
+		}
+
+		for i := range c.Rules {
+			o.set(fmt.Sprintf("rules[%d]", len(config.Rules)), s, "rules", i)
+			config.Rules = append(config.Rules, c.Rules[i])
+		}
+
+		if c.Bots.Mode != "" {
+			config.Bots.Mode = c.Bots.Mode
+			o.set("bots.mode", s, "bots", "mode")
+		}
+		config.Bots.Logins = append(config.Bots.Logins, c.Bots.Logins...)
+
+		mergeFields(reflect.ValueOf(&config.Send).Elem(), reflect.ValueOf(c.Send), func(name string) {
+			o.set("send."+name, s, "send", name)
+		})
+	}
+
+	return config, o
+}
+
+// mergeFields overrides fields of the dst struct with non-zero fields of the src struct
+func mergeFields(dst, src reflect.Value, onSet func(string)) {
+	for i := 0; i < src.NumField(); i++ {
+		if src.Field(i).IsZero() {
+			continue
+		}
+
+		dst.Field(i).Set(src.Field(i))
+		name, _, _ := strings.Cut(src.Type().Field(i).Tag.Get("yaml"), ",")
+		onSet(name)
+	}
+}
+
+func indexOfRemote(remotes []Remote, remote Remote) int {
+	for i := range remotes {
+		if remotes[i].Name == remote.Name && remotes[i].EnterpriseUrl == remote.EnterpriseUrl {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func documentContent(root *yaml.Node) *yaml.Node {
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		return root.Content[0]
+	}
+
+	return root
+}
+
+// findNode returns the closest existing node from the path
+// path contains field names and sequence indexes
+func findNode(node *yaml.Node, path ...interface{}) *yaml.Node {
+	for _, elem := range path {
+		var next *yaml.Node
+		switch e := elem.(type) {
+		case string:
+			next = mappingValue(node, e)
+		case int:
+			if node.Kind == yaml.SequenceNode && e < len(node.Content) {
+				next = node.Content[e]
+			}
+		}
+
+		if next == nil {
+			return node
+		}
+		node = next
+	}
+
+	return node
+}
@@ -0,0 +1,218 @@
+package config
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadWithIncludes(t *testing.T) {
+	t.Run("merge included files", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeTestFile(t, path.Join(tmpDir, "shared", "orgs.yaml"), `
+orgs:
+- name: kyma-project
+  token: shared-token
+- name: kyma-incubator
+bots:
+  mode: drop
+  logins: ["kyma-bot"]
+`)
+		writeTestFile(t, path.Join(tmpDir, "shared", "send.yaml"), `
+include: ["orgs.yaml"]
+send:
+  serverAddress: smtp.gmail.com
+  serverPort: 587
+  subject: PKUP report
+`)
+		configPath := path.Join(tmpDir, "config.yaml")
+		writeTestFile(t, configPath, `
+include:
+- shared/send.yaml
+- shared/orgs.yaml
+orgs:
+- name: kyma-project
+  token: team-token
+bots:
+  logins: ["team-bot"]
+send:
+  subject: team report
+reports:
+- signatures:
+  - username: pPrecel
+  email: test@test.com
+`)
+
+		config, err := Read(configPath)
+		require.NoError(t, err)
+		require.Equal(t, []Org{
+			{Remote: Remote{Name: "kyma-project", Token: "team-token"}},
+			{Remote: Remote{Name: "kyma-incubator"}},
+		}, config.Orgs)
+		require.Equal(t, Bots{Mode: "drop", Logins: []string{"kyma-bot", "team-bot"}}, config.Bots)
+		require.Equal(t, Send{ServerAddress: "smtp.gmail.com", ServerPort: 587, Subject: "team report"}, config.Send)
+		require.Len(t, config.Reports, 1)
+		require.Nil(t, config.Include)
+	})
+
+	t.Run("issues in included files", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		includedPath := path.Join(tmpDir, "repos.yaml")
+		writeTestFile(t, includedPath, `
+repos:
+- name: kyma-project
+`)
+		configPath := path.Join(tmpDir, "config.yaml")
+		writeTestFile(t, configPath, `
+include: ["repos.yaml"]
+reports:
+- signatures:
+  - username: pPrecel
+`)
+
+		config, err := Read(configPath)
+		require.Nil(t, config)
+		require.Equal(t, &ValidationError{Issues: []Issue{
+			{File: includedPath, Line: 3, Column: 9, Field: "repos[0].name", Message: "repo name 'kyma-project' is not in format <ORG>/<REPO>"},
+		}}, err)
+	})
+
+	t.Run("rebase paths of included files", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeTestFile(t, path.Join(tmpDir, "shared", "template.docx"), "")
+		writeTestFile(t, path.Join(tmpDir, "shared", "report.yaml"), `
+template: template.docx
+pdfLayout: layouts/pdf.yaml
+send:
+  htmlBodyPath: /abs/body.html
+reports:
+- signatures:
+  - username: pPrecel
+  email: test@test.com
+  outputDir: reports
+`)
+		configPath := path.Join(tmpDir, "config.yaml")
+		writeTestFile(t, configPath, `
+include: ["shared/report.yaml"]
+orgs:
+- name: kyma-project
+reports:
+- signatures:
+  - username: pPrecel
+  email: test@test.com
+  outputDir: local
+`)
+
+		config, err := Read(configPath)
+		require.NoError(t, err)
+		require.Equal(t, path.Join(tmpDir, "shared", "template.docx"), config.Template)
+		require.Equal(t, path.Join(tmpDir, "shared", "layouts", "pdf.yaml"), config.PDFLayout)
+		require.Equal(t, "/abs/body.html", config.Send.HTMLBodyPath)
+		require.Equal(t, path.Join(tmpDir, "shared", "reports"), config.Reports[0].OutputDir)
+		// paths of the main file stay relative to the working directory
+		require.Equal(t, "local", config.Reports[1].OutputDir)
+	})
+
+	t.Run("different tokens for the same enterpriseUrl", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := path.Join(tmpDir, "config.yaml")
+		writeTestFile(t, configPath, `
+orgs:
+- name: kyma-project
+  token: global-token
+reports:
+- signatures:
+  - username: pPrecel
+  repos:
+  - name: kyma/serverless
+    token: report-token
+`)
+
+		config, err := Read(configPath)
+		require.Nil(t, config)
+		require.Equal(t, &ValidationError{Issues: []Issue{
+			{File: configPath, Line: 10, Column: 12, Field: "reports[0].repos[0].token", Message: "token differs from the token of other orgs and repos with the enterpriseUrl ''"},
+		}}, err)
+	})
+
+	t.Run("different references to the same token", func(t *testing.T) {
+		t.Setenv("PKUP_TEST_TOKEN", "test-token")
+		tmpDir := t.TempDir()
+		tokenPath := path.Join(tmpDir, "token")
+		writeTestFile(t, tokenPath, "test-token\n")
+		configPath := path.Join(tmpDir, "config.yaml")
+		writeTestFile(t, configPath, `
+orgs:
+- name: kyma-project
+  token: ${PKUP_TEST_TOKEN}
+repos:
+- name: kyma-incubator/reconciler
+  token: env:PKUP_TEST_TOKEN
+reports:
+- signatures:
+  - username: pPrecel
+  repos:
+  - name: kyma/serverless
+    token: file:`+tokenPath+`
+`)
+
+		config, err := Read(configPath)
+		require.NoError(t, err)
+		require.Equal(t, "test-token", config.Reports[0].Repos[0].Token)
+	})
+
+	t.Run("include cycle", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeTestFile(t, path.Join(tmpDir, "a.yaml"), `include: ["b.yaml"]`)
+		writeTestFile(t, path.Join(tmpDir, "b.yaml"), `include: ["a.yaml"]`)
+
+		config, err := Read(path.Join(tmpDir, "a.yaml"))
+		require.Nil(t, config)
+		require.ErrorContains(t, err, "include cycle detected")
+	})
+
+	t.Run("missing included file", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeTestFile(t, path.Join(tmpDir, "config.yaml"), `include: ["missing.yaml"]`)
+
+		config, err := Read(path.Join(tmpDir, "config.yaml"))
+		require.Nil(t, config)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("report overrides", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := path.Join(tmpDir, "config.yaml")
+		writeTestFile(t, configPath, `
+orgs:
+- name: kyma-project
+reports:
+- signatures:
+  - username: pPrecel
+    enterpriseUrl: https://github.tools.sap/api/v3
+  repos:
+  - name: kyma/serverless
+    enterpriseUrl: https://github.tools.sap/api/v3
+  period:
+    since: 19.10.2023
+    until: 18.09.2023
+- signatures:
+  - username: pPrecel
+    enterpriseUrl: https://github.tools.sap/api/v3
+`)
+
+		config, err := Read(configPath)
+		require.Nil(t, config)
+		require.Equal(t, &ValidationError{Issues: []Issue{
+			{File: configPath, Line: 12, Column: 5, Field: "reports[0].period", Message: "period until '18.09.2023' is before since '19.10.2023'"},
+			{File: configPath, Line: 15, Column: 5, Field: "reports[1].signatures[0]", Message: "signature 'pPrecel' does not match any org or repo with the enterpriseUrl 'https://github.tools.sap/api/v3'"},
+		}}, err)
+	})
+}
+
+func writeTestFile(t *testing.T, filePath, content string) {
+	require.NoError(t, os.MkdirAll(path.Dir(filePath), os.ModePerm))
+	require.NoError(t, os.WriteFile(filePath, []byte(content), os.ModePerm))
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Marshal returns yaml config with fields described by comments from the config types
+// only the first item of every list is commented
+func Marshal(config *Config, header string) ([]byte, error) {
+	docs, err := parseFieldDocs(configSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config fields docs: %s", err.Error())
+	}
+
+	node := &yaml.Node{}
+	if err := node.Encode(config); err != nil {
+		return nil, err
+	}
+
+	addComments(node, reflect.TypeOf(config), docs)
+	document := &yaml.Node{
+		Kind:        yaml.DocumentNode,
+		HeadComment: header,
+		Content:     []*yaml.Node{node},
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func addComments(node *yaml.Node, t reflect.Type, docs map[string]string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		if t.Kind() != reflect.Struct {
+			return
+		}
+
+		fields := yamlFields(t)
+		for i := 1; i < len(node.Content); i += 2 {
+			key := node.Content[i-1]
+			key.HeadComment = docs[fieldDocKey(t, key.Value)]
+			addComments(node.Content[i], fields[key.Value], docs)
+		}
+	case yaml.SequenceNode:
+		if len(node.Content) > 0 && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			addComments(node.Content[0], t.Elem(), docs)
+		}
+	}
+}
+
+// fieldDocKey returns docs key of the field in format <TYPE>.<YAML_FIELD> looking also into inlined structs
+func fieldDocKey(t reflect.Type, name string) string {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldName, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if strings.Contains(opts, "inline") {
+			if key := fieldDocKey(field.Type, name); key != "" {
+				return key
+			}
+			continue
+		}
+
+		if fieldName == name {
+			return t.Name() + "." + name
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,210 @@
+package config
+
+import (
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pPrecel/PKUP/pkg/github"
+	"github.com/pPrecel/PKUP/pkg/report"
+)
+
+const (
+	SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+	SchemaTitle = "pkup compose config"
+)
+
+// source of the config types used to get fields descriptions
+//
+//go:embed config.go
+var configSource string
+
+// e.g.: "60s", "1h30m"
+const durationPattern = `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+
+// allowed values of fields in format <TYPE>.<YAML_FIELD>
+var schemaEnums = map[string][]string{
+	"Bots.mode":       github.BotCommitsModes,
+	"Report.formats":  report.Formats,
+	"Report.groupBy":  report.GroupByOptions,
+	"Report.locales":  report.Locales,
+	"Validation.mode": report.ValidationModes,
+}
+
+// default values of fields in format <TYPE>.<YAML_FIELD>
+// defaults described only with words in comments ( e.g. "use repo HEAD branch" ) are not listed
+var schemaDefaults = map[string]interface{}{
+	"Bots.mode":          github.BotCommitsInclude,
+	"Remote.allBranches": false,
+	"Remote.uniqueOnly":  false,
+	"Report.enrich":      false,
+	"Rule.botAuthor":     false,
+	"Rule.merge":         false,
+	"Rule.revertPairs":   false,
+	"Send.perDial":       1,
+	"Validation.mode":    report.ValidationModeWarn,
+}
+
+// formats of fields in format <TYPE>.<YAML_FIELD>
+var schemaFormats = map[string]string{
+	"Remote.enterpriseUrl":    "uri",
+	"Signature.enterpriseUrl": "uri",
+	"Report.email":            "email",
+	"Send.from":               "email",
+}
+
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+}
+
+// GenerateSchema returns JSON Schema of the Config based on its types and fields comments
+func GenerateSchema() (*Schema, error) {
+	docs, err := parseFieldDocs(configSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config fields docs: %s", err.Error())
+	}
+
+	schema := buildSchema(reflect.TypeOf(Config{}), docs)
+	schema.Schema = SchemaDraft
+	schema.Title = SchemaTitle
+
+	return schema, nil
+}
+
+// parseFieldDocs returns comments of struct fields in format <TYPE>.<YAML_FIELD>
+func parseFieldDocs(source string) (map[string]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "config.go", source, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := map[string]string{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		typeSpec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return false
+		}
+
+		for _, field := range structType.Fields.List {
+			if field.Tag == nil || len(field.Names) == 0 {
+				continue
+			}
+
+			tag, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				continue
+			}
+
+			name, _, _ := strings.Cut(reflect.StructTag(tag).Get("yaml"), ",")
+			docs[typeSpec.Name.Name+"."+name] = strings.TrimSpace(field.Doc.Text())
+		}
+
+		return false
+	})
+
+	return docs, nil
+}
+
+func buildSchema(t reflect.Type, docs map[string]string) *Schema {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return &Schema{
+			Type:    "string",
+			Pattern: durationPattern,
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return buildSchema(t.Elem(), docs)
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{
+			Type:  "array",
+			Items: buildSchema(t.Elem(), docs),
+		}
+	case reflect.Map:
+		return &Schema{
+			Type:                 "object",
+			AdditionalProperties: buildSchema(t.Elem(), docs),
+		}
+	case reflect.Struct:
+		return buildStructSchema(t, docs)
+	default:
+		return &Schema{}
+	}
+}
+
+// buildStructSchema returns object schema rejecting unknown fields like the config decoder
+func buildStructSchema(t reflect.Type, docs map[string]string) *Schema {
+	schema := &Schema{
+		Type:                 "object",
+		Properties:           map[string]*Schema{},
+		AdditionalProperties: false,
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if strings.Contains(opts, "inline") {
+			for inlineName, inlineSchema := range buildStructSchema(field.Type, docs).Properties {
+				schema.Properties[inlineName] = inlineSchema
+			}
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		key := t.Name() + "." + name
+		fieldSchema := buildSchema(field.Type, docs)
+		fieldSchema.Description = docs[key]
+		fieldSchema.Default = schemaDefaults[key]
+
+		valuesSchema := fieldSchema
+		if fieldSchema.Type == "array" {
+			valuesSchema = fieldSchema.Items
+		}
+		valuesSchema.Enum = schemaEnums[key]
+		if format, ok := schemaFormats[key]; ok {
+			valuesSchema.Format = format
+		}
+
+		schema.Properties[name] = fieldSchema
+	}
+
+	return schema
+}
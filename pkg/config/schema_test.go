@@ -0,0 +1,67 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/pPrecel/PKUP/pkg/report"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSchema(t *testing.T) {
+	schema, err := GenerateSchema()
+	require.NoError(t, err)
+
+	t.Run("root", func(t *testing.T) {
+		require.Equal(t, SchemaDraft, schema.Schema)
+		require.Equal(t, "object", schema.Type)
+		require.Equal(t, false, schema.AdditionalProperties)
+	})
+
+	t.Run("every field is described", func(t *testing.T) {
+		requireDescriptions(t, "", schema)
+	})
+
+	t.Run("inline fields", func(t *testing.T) {
+		org := schema.Properties["orgs"].Items
+		require.Contains(t, org.Properties, "ignoreRepos")
+		require.Contains(t, org.Properties, "name")
+		require.Equal(t, "uri", org.Properties["enterpriseUrl"].Format)
+	})
+
+	t.Run("enums, formats and defaults", func(t *testing.T) {
+		reports := schema.Properties["reports"].Items
+		require.Equal(t, report.Formats, reports.Properties["formats"].Items.Enum)
+		require.Equal(t, "email", reports.Properties["email"].Format)
+		require.Equal(t, "warn", reports.Properties["validation"].Properties["mode"].Default)
+		require.Equal(t, "object", reports.Properties["extraFields"].Type)
+		require.Equal(t, &Schema{Type: "string"}, reports.Properties["extraFields"].AdditionalProperties)
+
+		send := schema.Properties["send"]
+		require.Equal(t, 1, send.Properties["perDial"].Default)
+		require.Equal(t, "string", send.Properties["dialDelay"].Type)
+		require.Nil(t, send.Properties["dialDelay"].Default)
+
+		rule := schema.Properties["rules"].Items
+		require.Nil(t, rule.Properties["name"].Default)
+		require.Equal(t, false, rule.Properties["merge"].Default)
+	})
+}
+
+func Test_schemaDefaults(t *testing.T) {
+	docs, err := parseFieldDocs(configSource)
+	require.NoError(t, err)
+
+	for key := range schemaDefaults {
+		require.Contains(t, docs, key, "default of unknown field '%s'", key)
+	}
+}
+
+func requireDescriptions(t *testing.T, path string, schema *Schema) {
+	for name, property := range schema.Properties {
+		require.NotEmpty(t, property.Description, "missing description of '%s%s'", path, name)
+		requireDescriptions(t, path+name+".", property)
+		if property.Items != nil {
+			requireDescriptions(t, path+name+"[].", property.Items)
+		}
+	}
+}
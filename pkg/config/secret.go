@@ -0,0 +1,151 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	// e.g.: "env:GITHUB_TOKEN"
+	SecretRefEnv = "env:"
+	// e.g.: "file:/home/user/.pkup/token"
+	SecretRefFile = "file:"
+	// e.g.: "keyring:pkup/github-token"
+	SecretRefKeyring = "keyring:"
+	// e.g.: "cmd:pass show pkup/smtp"
+	SecretRefCmd = "cmd:"
+)
+
+// e.g.: "${GITHUB_TOKEN}"
+var envVarRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// resolveSecrets replaces secret references and ${ENV} variables in sensitive fields with their values
+// resolved values are never part of returned issues
+func resolveSecrets(config *Config, origins origins) []Issue {
+	v := validator{origins: origins}
+
+	for i := range config.Orgs {
+		token, err := resolveSecret(config.Orgs[i].Token)
+		config.Orgs[i].Token = token
+		v.check(err, "orgs", i, "token")
+	}
+
+	for i := range config.Repos {
+		token, err := resolveSecret(config.Repos[i].Token)
+		config.Repos[i].Token = token
+		v.check(err, "repos", i, "token")
+	}
+
+	for i := range config.Reports {
+		for j := range config.Reports[i].Orgs {
+			token, err := resolveSecret(config.Reports[i].Orgs[j].Token)
+			config.Reports[i].Orgs[j].Token = token
+			v.check(err, "reports", i, "orgs", j, "token")
+		}
+
+		for j := range config.Reports[i].Repos {
+			token, err := resolveSecret(config.Reports[i].Repos[j].Token)
+			config.Reports[i].Repos[j].Token = token
+			v.check(err, "reports", i, "repos", j, "token")
+		}
+	}
+
+	username, err := resolveSecret(config.Send.Username)
+	config.Send.Username = username
+	v.check(err, "send", "username")
+
+	password, err := resolveSecret(config.Send.Password)
+	config.Send.Password = password
+	v.check(err, "send", "password")
+
+	return v.issues
+}
+
+// resolveSecret returns value of the secret reference or the value with expanded ${ENV} variables
+func resolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, SecretRefEnv):
+		return lookupEnv(strings.TrimPrefix(value, SecretRefEnv))
+	case strings.HasPrefix(value, SecretRefFile):
+		return readSecretFile(strings.TrimPrefix(value, SecretRefFile))
+	case strings.HasPrefix(value, SecretRefKeyring):
+		return getKeyringSecret(strings.TrimPrefix(value, SecretRefKeyring))
+	case strings.HasPrefix(value, SecretRefCmd):
+		return runSecretCmd(strings.TrimPrefix(value, SecretRefCmd))
+	default:
+		return expandEnv(value)
+	}
+}
+
+func lookupEnv(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable '%s' is not set", name)
+	}
+
+	return value, nil
+}
+
+// expandEnv replaces only variables in format ${NAME} to not break values containing the '$' character
+func expandEnv(value string) (string, error) {
+	var errs []string
+	expanded := envVarRegex.ReplaceAllStringFunc(value, func(match string) string {
+		envValue, err := lookupEnv(envVarRegex.FindStringSubmatch(match)[1])
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+
+		return envValue
+	})
+	if len(errs) > 0 {
+		return "", errors.New(strings.Join(errs, ", "))
+	}
+
+	return expanded, nil
+}
+
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file '%s': %s", path, err.Error())
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// getKeyringSecret reads secret from the system keyring based on the reference in format <SERVICE>/<USER>
+func getKeyringSecret(ref string) (string, error) {
+	service, user, ok := strings.Cut(ref, "/")
+	if !ok || service == "" || user == "" {
+		return "", fmt.Errorf("keyring reference '%s' is not in format <SERVICE>/<USER>", ref)
+	}
+
+	secret, err := keyring.Get(service, user)
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret for '%s' from keyring: %s", ref, err.Error())
+	}
+
+	return secret, nil
+}
+
+// runSecretCmd returns trimmed stdout of the command
+// command is run without shell so pipes and redirections are not supported
+func runSecretCmd(command string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", errors.New("secret command is empty")
+	}
+
+	out, err := exec.Command(args[0], args[1:]...).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run secret command '%s': %s", args[0], err.Error())
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
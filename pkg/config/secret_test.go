@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+func Test_resolveSecret(t *testing.T) {
+	keyring.MockInit()
+	require.NoError(t, keyring.Set("pkup", "github-token", "keyring-token"))
+
+	secretPath := path.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(secretPath, []byte("file-token\n"), 0600))
+
+	t.Setenv("PKUP_TEST_TOKEN", "env-token")
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "plain value",
+			value: "pa$$word",
+			want:  "pa$$word",
+		},
+		{
+			name:  "expand env variables",
+			value: "prefix-${PKUP_TEST_TOKEN}-$PKUP_TEST_TOKEN",
+			want:  "prefix-env-token-$PKUP_TEST_TOKEN",
+		},
+		{
+			name:    "missing env variable",
+			value:   "${PKUP_TEST_MISSING}",
+			wantErr: "environment variable 'PKUP_TEST_MISSING' is not set",
+		},
+		{
+			name:  "env reference",
+			value: "env:PKUP_TEST_TOKEN",
+			want:  "env-token",
+		},
+		{
+			name:  "file reference",
+			value: "file:" + secretPath,
+			want:  "file-token",
+		},
+		{
+			name:  "keyring reference",
+			value: "keyring:pkup/github-token",
+			want:  "keyring-token",
+		},
+		{
+			name:    "wrong keyring reference",
+			value:   "keyring:pkup",
+			wantErr: "keyring reference 'pkup' is not in format <SERVICE>/<USER>",
+		},
+		{
+			name:  "cmd reference",
+			value: "cmd:echo cmd-token",
+			want:  "cmd-token",
+		},
+		{
+			name:    "empty cmd reference",
+			value:   "cmd: ",
+			wantErr: "secret command is empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveSecret(tt.value)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
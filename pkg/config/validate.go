@@ -0,0 +1,436 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pPrecel/PKUP/pkg/github"
+	"github.com/pPrecel/PKUP/pkg/report"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// e.g.: "kyma-project"
+	orgNameRegex = regexp.MustCompile(`^[\w.-]+$`)
+	// e.g.: "kyma-project/serverless"
+	repoNameRegex = regexp.MustCompile(`^[\w.-]+/[\w.-]+$`)
+	// e.g.: "line 12: cannot unmarshal !!str `abc` into int"
+	typeErrorRegex = regexp.MustCompile(`^line (\d+): (.*)$`)
+)
+
+// Issue is a single problem found in the config file
+type Issue struct {
+	// path to the config file with the problem
+	File string
+	// position of the problem in the file ( 0 when unknown )
+	Line   int
+	Column int
+	// path to the field with the problem
+	// e.g.: "reports[0].signatures[1]"
+	Field   string
+	Message string
+}
+
+func (i Issue) String() string {
+	message := i.Message
+	if i.Field != "" {
+		message = fmt.Sprintf("%s: %s", i.Field, i.Message)
+	}
+
+	position := ""
+	switch {
+	case i.Line > 0 && i.Column > 0:
+		position = fmt.Sprintf("line %d, column %d", i.Line, i.Column)
+	case i.Line > 0:
+		position = fmt.Sprintf("line %d", i.Line)
+	}
+
+	if i.File != "" {
+		position = strings.TrimSpace(fmt.Sprintf("%s %s", filepath.Base(i.File), position))
+	}
+
+	if position == "" {
+		return message
+	}
+
+	return fmt.Sprintf("%s: %s", position, message)
+}
+
+// ValidationError contains all issues found in the config file
+type ValidationError struct {
+	Issues []Issue
+}
+
+func (e *ValidationError) Error() string {
+	issues := make([]string, len(e.Issues))
+	for i := range e.Issues {
+		issues[i] = e.Issues[i].String()
+	}
+
+	return fmt.Sprintf("found %d config issues: %s", len(issues), strings.Join(issues, "; "))
+}
+
+// load reads the config file with all included files, merges and validates them
+func load(path string) (*Config, error) {
+	sources, err := readSources(path, map[string]bool{}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := []Issue{}
+	invalid := false
+	for _, s := range sources {
+		issues = append(issues, s.issues...)
+		invalid = invalid || s.invalid
+	}
+
+	if invalid {
+		// values are not validated when some of them can't be decoded
+		return nil, &ValidationError{Issues: issues}
+	}
+
+	config, origins := merge(sources)
+	issues = append(issues, validate(config, origins)...)
+	if len(issues) == 0 {
+		issues = resolveSecrets(config, origins)
+	}
+
+	if len(issues) == 0 {
+		// different references can point to the same secret so tokens are compared after resolving them
+		issues = validateTokens(config, origins)
+	}
+
+	if len(issues) > 0 {
+		return nil, &ValidationError{Issues: issues}
+	}
+
+	return config, nil
+}
+
+// findUnknownFields walks the yaml tree looking for keys not matching any yaml tag of the given type
+func findUnknownFields(node *yaml.Node, t reflect.Type, path string) []Issue {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	issues := []Issue{}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, content := range node.Content {
+			issues = append(issues, findUnknownFields(content, t, path)...)
+		}
+	case yaml.MappingNode:
+		if t.Kind() == reflect.Map {
+			for i := 1; i < len(node.Content); i += 2 {
+				issues = append(issues, findUnknownFields(node.Content[i], t.Elem(), joinField(path, node.Content[i-1].Value))...)
+			}
+		}
+		if t.Kind() != reflect.Struct {
+			// wrong types are reported by the decoder
+			return issues
+		}
+
+		fields := yamlFields(t)
+		for i := 1; i < len(node.Content); i += 2 {
+			key := node.Content[i-1]
+			fieldType, ok := fields[key.Value]
+			if !ok {
+				issues = append(issues, Issue{
+					Line:    key.Line,
+					Column:  key.Column,
+					Field:   path,
+					Message: unknownFieldMessage(key.Value, fields),
+				})
+				continue
+			}
+
+			issues = append(issues, findUnknownFields(node.Content[i], fieldType, joinField(path, key.Value))...)
+		}
+	case yaml.SequenceNode:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return issues
+		}
+
+		for i, content := range node.Content {
+			issues = append(issues, findUnknownFields(content, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+
+	return issues
+}
+
+// yamlFields returns types of struct fields by their yaml names including inlined structs
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if strings.Contains(opts, "inline") {
+			for inlineName, inlineType := range yamlFields(field.Type) {
+				fields[inlineName] = inlineType
+			}
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Type
+	}
+
+	return fields
+}
+
+func unknownFieldMessage(name string, fields map[string]reflect.Type) string {
+	for known := range fields {
+		if strings.EqualFold(known, name) {
+			return fmt.Sprintf("unknown field '%s' (did you mean '%s'?)", name, known)
+		}
+	}
+
+	return fmt.Sprintf("unknown field '%s'", name)
+}
+
+// toTypeIssues converts decoder errors reported only with line numbers
+func toTypeIssues(root *yaml.Node, err *yaml.TypeError) []Issue {
+	issues := []Issue{}
+	for _, e := range err.Errors {
+		issue := Issue{Message: e}
+		if match := typeErrorRegex.FindStringSubmatch(e); match != nil {
+			issue.Line, _ = strconv.Atoi(match[1])
+			issue.Column = findValueColumn(root, issue.Line)
+			issue.Message = match[2]
+		}
+
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
+
+// findValueColumn returns column of the last node starting in the given line
+func findValueColumn(node *yaml.Node, line int) int {
+	column := 0
+	if node.Line == line {
+		column = node.Column
+	}
+
+	for _, content := range node.Content {
+		if c := findValueColumn(content, line); c > column {
+			column = c
+		}
+	}
+
+	return column
+}
+
+// validate checks values that can't be verified by the decoder
+func validate(config *Config, origins origins) []Issue {
+	v := validator{origins: origins}
+
+	v.checkTemplate(config.Template, "template")
+	globalUrls := v.checkSources(config.Orgs, config.Repos)
+
+	v.check(github.ValidateBotCommitsMode(config.Bots.Mode), "bots", "mode")
+
+	sendConfigured := config.Send != Send{}
+	for i, user := range config.Reports {
+		v.checkTemplate(user.Template, "reports", i, "template")
+
+		remoteUrls := globalUrls
+		if len(user.Orgs) > 0 || len(user.Repos) > 0 {
+			remoteUrls = v.checkSources(user.Orgs, user.Repos, "reports", i)
+		}
+
+		if len(user.Signatures) == 0 {
+			v.add("report has no signatures", "reports", i)
+		}
+
+		for j, signature := range user.Signatures {
+			if _, ok := remoteUrls[signature.EnterpriseUrl]; !ok {
+				v.add(fmt.Sprintf("signature '%s' does not match any org or repo with the enterpriseUrl '%s'",
+					signature.Username, signature.EnterpriseUrl), "reports", i, "signatures", j)
+			}
+		}
+
+		if sendConfigured && user.Email == "" {
+			v.add("email is required when the send config is set", "reports", i)
+		}
+
+		for j, format := range user.Formats {
+			v.check(report.ValidateFormat(format), "reports", i, "formats", j)
+		}
+
+		for j, locale := range user.Locales {
+			v.check(report.ValidateLocale(locale), "reports", i, "locales", j)
+		}
+
+		v.check(report.ValidateGroupBy(user.GroupBy), "reports", i, "groupBy")
+		v.check(report.ValidateValidationMode(user.Validation.Mode), "reports", i, "validation", "mode")
+
+		_, _, err := user.Period.Bounds(time.Time{}, time.Now())
+		v.check(err, "reports", i, "period")
+	}
+
+	return v.issues
+}
+
+// validateTokens checks if all orgs and repos with the same enterprise url use the same resolved token
+// only one client is built for every enterprise url so other tokens would be ignored
+func validateTokens(config *Config, origins origins) []Issue {
+	v := validator{origins: origins, tokens: map[string]string{}}
+
+	for i := range config.Orgs {
+		v.checkToken(config.Orgs[i].Remote, "orgs", i, "token")
+	}
+
+	for i := range config.Repos {
+		v.checkToken(config.Repos[i], "repos", i, "token")
+	}
+
+	for i := range config.Reports {
+		for j := range config.Reports[i].Orgs {
+			v.checkToken(config.Reports[i].Orgs[j].Remote, "reports", i, "orgs", j, "token")
+		}
+
+		for j := range config.Reports[i].Repos {
+			v.checkToken(config.Reports[i].Repos[j], "reports", i, "repos", j, "token")
+		}
+	}
+
+	return v.issues
+}
+
+type validator struct {
+	origins origins
+	issues  []Issue
+	// first token set for the enterprise url ( all orgs and repos with the url share one GitHub client )
+	tokens map[string]string
+}
+
+func (v *validator) check(err error, path ...interface{}) {
+	if err != nil {
+		v.add(err.Error(), path...)
+	}
+}
+
+func (v *validator) checkTemplate(template string, path ...interface{}) {
+	if template == "" {
+		return
+	}
+
+	if _, err := os.Stat(template); err != nil {
+		v.add(fmt.Sprintf("template file '%s' does not exist", template), path...)
+	}
+}
+
+// checkSources validates names of orgs and repos and returns their enterprise urls
+func (v *validator) checkSources(orgs []Org, repos []Remote, path ...interface{}) map[string]struct{} {
+	remoteUrls := map[string]struct{}{}
+	for i, org := range orgs {
+		remoteUrls[org.EnterpriseUrl] = struct{}{}
+		if !orgNameRegex.MatchString(org.Name) {
+			v.add(fmt.Sprintf("org name '%s' is not in format <ORG>", org.Name), appendPath(path, "orgs", i, "name")...)
+		}
+	}
+
+	for i, repo := range repos {
+		remoteUrls[repo.EnterpriseUrl] = struct{}{}
+		if !repoNameRegex.MatchString(repo.Name) {
+			v.add(fmt.Sprintf("repo name '%s' is not in format <ORG>/<REPO>", repo.Name), appendPath(path, "repos", i, "name")...)
+		}
+	}
+
+	return remoteUrls
+}
+
+// checkToken reports tokens different from the token set earlier for the same enterprise url
+// the message never contains tokens
+func (v *validator) checkToken(remote Remote, path ...interface{}) {
+	if remote.Token == "" {
+		return
+	}
+
+	token, ok := v.tokens[remote.EnterpriseUrl]
+	if !ok {
+		v.tokens[remote.EnterpriseUrl] = remote.Token
+		return
+	}
+
+	if token != remote.Token {
+		v.add(fmt.Sprintf("token differs from the token of other orgs and repos with the enterpriseUrl '%s'", remote.EnterpriseUrl), path...)
+	}
+}
+
+// add saves issue with position of the closest existing node from the path
+// path contains field names and sequence indexes
+func (v *validator) add(message string, path ...interface{}) {
+	fields := []string{""}
+	for _, elem := range path {
+		switch e := elem.(type) {
+		case string:
+			fields = append(fields, joinField(fields[len(fields)-1], e))
+		case int:
+			fields = append(fields, fmt.Sprintf("%s[%d]", fields[len(fields)-1], e))
+		}
+	}
+
+	// look for the file where the longest part of the path is defined
+	for i := len(path); i >= 0; i-- {
+		o, ok := v.origins[fields[i]]
+		if !ok {
+			continue
+		}
+
+		node := findNode(o.node, path[i:]...)
+		v.issues = append(v.issues, Issue{
+			File:    o.path,
+			Line:    node.Line,
+			Column:  node.Column,
+			Field:   fields[len(fields)-1],
+			Message: message,
+		})
+		return
+	}
+
+	v.issues = append(v.issues, Issue{
+		Field:   fields[len(fields)-1],
+		Message: message,
+	})
+}
+
+func appendPath(path []interface{}, elems ...interface{}) []interface{} {
+	return append(append([]interface{}{}, path...), elems...)
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 1; i < len(node.Content); i += 2 {
+		if node.Content[i-1].Value == key {
+			return node.Content[i]
+		}
+	}
+
+	return nil
+}
+
+func joinField(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
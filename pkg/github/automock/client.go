@@ -38,6 +38,32 @@ func (_m *Client) GetCommitContentDiff(_a0 *github.RepositoryCommit, _a1 string,
 	return r0, r1
 }
 
+// GetIssue provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) GetIssue(_a0 string, _a1 string, _a2 int) (*pkggithub.Issue, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *pkggithub.Issue
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, int) (*pkggithub.Issue, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, int) *pkggithub.Issue); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pkggithub.Issue)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, int) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLatestReleaseOrZero provides a mock function with given fields: _a0, _a1
 func (_m *Client) GetLatestReleaseOrZero(_a0 string, _a1 string) (string, error) {
 	ret := _m.Called(_a0, _a1)
@@ -88,6 +114,32 @@ func (_m *Client) GetUserSignatures(_a0 string) ([]string, error) {
 	return r0, r1
 }
 
+// ListCommitPullRequests provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) ListCommitPullRequests(_a0 string, _a1 string, _a2 string) ([]pkggithub.PullRequest, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 []pkggithub.PullRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) ([]pkggithub.PullRequest, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) []pkggithub.PullRequest); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pkggithub.PullRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListRepoBranches provides a mock function with given fields: _a0, _a1
 func (_m *Client) ListRepoBranches(_a0 string, _a1 string) (*pkggithub.BranchList, error) {
 	ret := _m.Called(_a0, _a1)
@@ -166,6 +218,32 @@ func (_m *Client) ListRepos(_a0 string) ([]string, error) {
 	return r0, r1
 }
 
+// ListUserOrgs provides a mock function with given fields: _a0
+func (_m *Client) ListUserOrgs(_a0 string) ([]string, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]string, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewClient creates a new instance of Client. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewClient(t interface {
@@ -23,7 +23,7 @@ func (gh *gh_client) ListRepoBranches(org, repo string) (*BranchList, error) {
 func (gh *gh_client) listBranchesForPage(dest *BranchList, org, repo string) pageListFunc {
 	return func(page int) (bool, error) {
 		perPage := 100
-		branches, resp, err := retryOnRateLimit(gh.log, func() ([]*go_github.Branch, *go_github.Response, error) {
+		branches, resp, err := retryOnRateLimit(gh.ctx, gh.log, func() ([]*go_github.Branch, *go_github.Response, error) {
 			return gh.client.Repositories.ListBranches(gh.ctx, org, repo, &go_github.BranchListOptions{
 				ListOptions: go_github.ListOptions{
 					Page:    page,
@@ -32,7 +32,7 @@ func (gh *gh_client) listBranchesForPage(dest *BranchList, org, repo string) pag
 			})
 		})
 		// return error only when statusCode is not 409 (repo is empty)
-		if err != nil && resp.StatusCode != 409 {
+		if err != nil && (resp == nil || resp.StatusCode != 409) {
 			return false, err
 		}
 
@@ -2,6 +2,8 @@ package github
 
 import (
 	"context"
+	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/go-github/v53/github"
@@ -12,10 +14,13 @@ import (
 type Client interface {
 	ListRepoCommits(ListRepoCommitsOpts) (*CommitList, error)
 	ListRepos(string) ([]string, error)
+	ListUserOrgs(string) ([]string, error)
 	ListRepoBranches(string, string) (*BranchList, error)
 	GetCommitContentDiff(*github.RepositoryCommit, string, string) (string, error)
 	GetLatestReleaseOrZero(string, string) (string, error)
 	GetUserSignatures(string) ([]string, error)
+	ListCommitPullRequests(string, string, string) ([]PullRequest, error)
+	GetIssue(string, string, int) (*Issue, error)
 }
 
 type gh_client struct {
@@ -27,10 +32,20 @@ type gh_client struct {
 type ClientOpts struct {
 	Token         string
 	EnterpriseURL string
+	// incremented for every request sent to the API ( optional )
+	APICalls *atomic.Int64
 }
 
 func NewClient(ctx context.Context, logger *pterm.Logger, opts ClientOpts) (Client, error) {
 	client := github.NewTokenClient(ctx, opts.Token)
+	if opts.APICalls != nil {
+		httpClient := client.Client()
+		httpClient.Transport = &countingTransport{
+			next:  httpClient.Transport,
+			count: opts.APICalls,
+		}
+		client = github.NewClient(httpClient)
+	}
 
 	if opts.EnterpriseURL != "" {
 		logger.Trace("building enterprise client", logger.Args(
@@ -56,7 +71,26 @@ func NewClient(ctx context.Context, logger *pterm.Logger, opts ClientOpts) (Clie
 	}, nil
 }
 
-func retryOnRateLimit[T any](log *pterm.Logger, fn func() (T, *github.Response, error)) (T, *github.Response, error) {
+// countingTransport counts all requests including retries
+type countingTransport struct {
+	next  http.RoundTripper
+	count *atomic.Int64
+}
+
+func (ct *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ct.count.Add(1)
+
+	next := ct.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return next.RoundTrip(req)
+}
+
+// retryOnRateLimit calls fn again after the rate limit reset
+// waiting is stopped when the ctx is canceled
+func retryOnRateLimit[T any](ctx context.Context, log *pterm.Logger, fn func() (T, *github.Response, error)) (T, *github.Response, error) {
 	var value T
 	var resp *github.Response
 	var err error
@@ -70,7 +104,11 @@ func retryOnRateLimit[T any](log *pterm.Logger, fn func() (T, *github.Response,
 			// https://docs.github.com/en/rest/using-the-rest-api/rate-limits-for-the-rest-api?apiVersion=2022-11-28
 			d := getRateLimitResetDuration(err)
 			log.Warn("Rate limit exceeded, waiting", log.Args("duration", d, "error", err.Error()))
-			time.Sleep(d)
+			select {
+			case <-ctx.Done():
+				return value, resp, ctx.Err()
+			case <-time.After(d):
+			}
 			continue
 		}
 
@@ -0,0 +1,85 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v53/github"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_retryOnRateLimit(t *testing.T) {
+	t.Run("stop waiting for rate limit reset when canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+
+		_, _, err := retryOnRateLimit(ctx, fixLogger(), func() (string, *github.Response, error) {
+			calls++
+			return "", nil, fixRateLimitError(time.Now().Add(time.Hour))
+		})
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("retry after rate limit reset", func(t *testing.T) {
+		calls := 0
+		value, _, err := retryOnRateLimit(context.Background(), fixLogger(), func() (string, *github.Response, error) {
+			calls++
+			if calls == 1 {
+				return "", nil, fixRateLimitError(time.Now())
+			}
+
+			return "value", nil, nil
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "value", value)
+		require.Equal(t, 2, calls)
+	})
+}
+
+func fixRateLimitError(reset time.Time) *github.RateLimitError {
+	return &github.RateLimitError{
+		Rate: github.Rate{
+			Reset: github.Timestamp{Time: reset},
+		},
+		Response: &http.Response{
+			Request: &http.Request{
+				Method: http.MethodGet,
+				URL:    &url.URL{},
+			},
+		},
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	t.Run("count API calls", func(t *testing.T) {
+		server := fixTestServer(t, &testServerArgs{
+			orgs: []*github.Organization{
+				{Login: github.String("test-org")},
+			},
+		})
+		defer server.Close()
+
+		apiCalls := &atomic.Int64{}
+		client, err := NewClient(context.Background(), fixLogger(), ClientOpts{
+			EnterpriseURL: server.URL + "/",
+			APICalls:      apiCalls,
+		})
+		require.NoError(t, err)
+
+		orgs, err := client.ListUserOrgs("test-user")
+		require.NoError(t, err)
+		require.Equal(t, []string{"test-org"}, orgs)
+		require.Equal(t, int64(1), apiCalls.Load())
+	})
+}
@@ -9,17 +9,55 @@ import (
 )
 
 type CommitList struct {
-	Commits []*go_github.RepositoryCommit
+	Commits []*go_github.RepositoryCommit `json:"commits"`
 }
 
 func (cl *CommitList) Append(from *CommitList) {
 	cl.Commits = append(cl.Commits, from.Commits...)
 }
 
+const (
+	// bot commits are treated like any other commit
+	BotCommitsInclude = "include"
+	// bot commits are removed from user commits
+	BotCommitsDrop = "drop"
+	// bot commits are kept and marked in reports
+	BotCommitsFlag = "flag"
+)
+
+// list of all supported modes of handling bot commits
+var BotCommitsModes = []string{
+	BotCommitsInclude,
+	BotCommitsDrop,
+	BotCommitsFlag,
+}
+
+type BotOptions struct {
+	// one of BotCommitsModes ( default: include )
+	Mode string
+	// additional logins or names of bot accounts ( e.g. internal automation users )
+	Logins []string
+}
+
+func ValidateBotCommitsMode(mode string) error {
+	if mode == "" {
+		return nil
+	}
+
+	for _, m := range BotCommitsModes {
+		if m == mode {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported bot commits mode '%s' (supported: %s)", mode, strings.Join(BotCommitsModes, ", "))
+}
+
 type ListRepoCommitsOpts struct {
 	Org        string
 	Repo       string
 	Authors    []string
+	Bots       BotOptions
 	Branches   []string
 	UniqueOnly bool
 	Since      time.Time
@@ -52,7 +90,7 @@ func (gh *gh_client) ListRepoCommits(opts ListRepoCommitsOpts) (*CommitList, err
 
 	// filter out not user commits
 	if len(opts.Authors) > 0 {
-		commits.Commits = GetUserCommits(commits.Commits, opts.Authors)
+		commits.Commits = GetUserCommits(commits.Commits, opts.Authors, opts.Bots)
 	}
 
 	// remove same commits from different branches
@@ -63,16 +101,24 @@ func (gh *gh_client) ListRepoCommits(opts ListRepoCommitsOpts) (*CommitList, err
 	return commits, nil
 }
 
-func GetUserCommits(commits []*go_github.RepositoryCommit, authors []string) []*go_github.RepositoryCommit {
+// GetUserCommits returns commits authored or co-authored by any of authors
+// commits created by bots ( e.g. dependabot commits co-authored by the user ) are dropped in the BotCommitsDrop mode
+// returned commits are shallow copies so their stats can be filled without changing commits shared between users
+func GetUserCommits(commits []*go_github.RepositoryCommit, authors []string, bots BotOptions) []*go_github.RepositoryCommit {
 	userCommits := []*go_github.RepositoryCommit{}
 
 	for _, commit := range commits {
+		if bots.Mode == BotCommitsDrop && IsBotCommit(commit, bots.Logins) {
+			continue
+		}
+
 		for _, author := range authors {
 			if isVerifiedCommitAuthor(commit, author) ||
 				isRepositoryCommitAuthor(commit, author) ||
 				isCommitAuthor(commit.Commit, author) {
 
-				userCommits = append(userCommits, commit)
+				userCommit := *commit
+				userCommits = append(userCommits, &userCommit)
 				break
 			}
 		}
@@ -92,7 +138,7 @@ type listForPageOpts struct {
 func (gh *gh_client) listCommitsPageFunc(dest *CommitList, opts listForPageOpts) pageListFunc {
 	return func(page int) (bool, error) {
 		perPage := 100
-		commits, resp, err := retryOnRateLimit(gh.log, func() ([]*go_github.RepositoryCommit, *go_github.Response, error) {
+		commits, resp, err := retryOnRateLimit(gh.ctx, gh.log, func() ([]*go_github.RepositoryCommit, *go_github.Response, error) {
 			return gh.client.Repositories.ListCommits(gh.ctx, opts.org, opts.repo, &go_github.CommitsListOptions{
 				SHA:   opts.branch,
 				Since: opts.since,
@@ -104,7 +150,7 @@ func (gh *gh_client) listCommitsPageFunc(dest *CommitList, opts listForPageOpts)
 			})
 		})
 		// return error only when statusCode is not 409 (repo is empty)
-		if err != nil && resp.StatusCode != 409 {
+		if err != nil && (resp == nil || resp.StatusCode != 409) {
 			return false, err
 		}
 
@@ -179,6 +225,43 @@ func isRepositoryCommitAuthor(commit *go_github.RepositoryCommit, author string)
 	return false
 }
 
+// IsBotCommit returns true if the commit was authored or committed by the bot account
+// accounts are recognized by the 'Bot' type, the '[bot]' login suffix ( e.g. dependabot[bot] ) or the logins list
+func IsBotCommit(commit *go_github.RepositoryCommit, logins []string) bool {
+	if commit == nil {
+		return false
+	}
+
+	for _, user := range []*go_github.User{commit.Author, commit.Committer} {
+		if user != nil &&
+			(user.GetType() == "Bot" || isBotLogin(user.GetLogin(), logins)) {
+			return true
+		}
+	}
+
+	// commits made on behalf of the user ( e.g. by GitHub Actions ) keep the bot in the git committer
+	return isBotLogin(commit.GetCommit().GetAuthor().GetName(), logins) ||
+		isBotLogin(commit.GetCommit().GetCommitter().GetName(), logins)
+}
+
+func isBotLogin(login string, logins []string) bool {
+	if login == "" {
+		return false
+	}
+
+	if strings.HasSuffix(login, "[bot]") {
+		return true
+	}
+
+	for _, l := range logins {
+		if strings.EqualFold(l, login) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func removeDuplicates(commitList *CommitList) {
 	commits := []*go_github.RepositoryCommit{}
 	for _, commit := range commitList.Commits {
@@ -180,3 +180,97 @@ func Test_gh_client_ListRepoCommits(t *testing.T) {
 		require.ElementsMatch(t, append(testCommits, testVerifiedCommit...), commitList.Commits)
 	})
 }
+
+func TestIsBotCommit(t *testing.T) {
+	tests := []struct {
+		name   string
+		commit *go_github.RepositoryCommit
+		want   bool
+	}{
+		{
+			name:   "bot type",
+			commit: &go_github.RepositoryCommit{Author: &go_github.User{Login: ptr.To("renovate"), Type: ptr.To("Bot")}},
+			want:   true,
+		},
+		{
+			name:   "bot login",
+			commit: &go_github.RepositoryCommit{Author: &go_github.User{Login: ptr.To("dependabot[bot]")}},
+			want:   true,
+		},
+		{
+			name: "bot git author",
+			commit: &go_github.RepositoryCommit{Commit: &go_github.Commit{
+				Author: &go_github.CommitAuthor{Name: ptr.To("github-actions[bot]")},
+			}},
+			want: true,
+		},
+		{
+			name: "bot git committer",
+			commit: &go_github.RepositoryCommit{Commit: &go_github.Commit{
+				Author:    &go_github.CommitAuthor{Name: ptr.To("John Wick")},
+				Committer: &go_github.CommitAuthor{Name: ptr.To("github-actions[bot]")},
+			}},
+			want: true,
+		},
+		{
+			name:   "configured bot login",
+			commit: &go_github.RepositoryCommit{Author: &go_github.User{Login: ptr.To("Internal-Automation"), Type: ptr.To("User")}},
+			want:   true,
+		},
+		{
+			name:   "user",
+			commit: &go_github.RepositoryCommit{Author: &go_github.User{Login: ptr.To("pPrecel"), Type: ptr.To("User")}},
+			want:   false,
+		},
+		{
+			name:   "nil",
+			commit: nil,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, IsBotCommit(tt.commit, []string{"internal-automation"}))
+		})
+	}
+}
+
+func TestGetUserCommits(t *testing.T) {
+	botCommit := &go_github.RepositoryCommit{
+		SHA:    ptr.To("bot-sha"),
+		Author: &go_github.User{Login: ptr.To("dependabot[bot]"), Type: ptr.To("Bot")},
+		Commit: &go_github.Commit{
+			Verification: &go_github.SignatureVerification{
+				Verified: ptr.To(true),
+				Payload:  ptr.To("author dependabot[bot] <support@github.com> 1697452255 +0200\n\nbump lib\n\nCo-authored-by: test-name <test@email.com>"),
+			},
+		},
+	}
+	userCommit := &go_github.RepositoryCommit{
+		SHA:    ptr.To("user-sha"),
+		Author: &go_github.User{Login: ptr.To("test-login")},
+	}
+	commits := []*go_github.RepositoryCommit{botCommit, userCommit}
+
+	t.Run("include bot commits co-authored by user", func(t *testing.T) {
+		userCommits := GetUserCommits(commits, []string{"test-name", "test-login"}, BotOptions{})
+		require.Equal(t, commits, userCommits)
+	})
+
+	t.Run("drop bot commits", func(t *testing.T) {
+		userCommits := GetUserCommits(commits, []string{"test-name", "test-login"}, BotOptions{
+			Mode: BotCommitsDrop,
+		})
+		require.Equal(t, []*go_github.RepositoryCommit{userCommit}, userCommits)
+	})
+
+	t.Run("don't modify listed commits", func(t *testing.T) {
+		userCommits := GetUserCommits(commits, []string{"test-login"}, BotOptions{})
+		require.Len(t, userCommits, 1)
+
+		SetDiffStats(userCommits[0], "diff --git a/main.go b/main.go\n@@ -1 +1 @@\n+ anything\n")
+		require.Equal(t, 1, userCommits[0].GetStats().GetAdditions())
+		require.Nil(t, userCommit.Stats)
+		require.Nil(t, userCommit.Files)
+	})
+}
@@ -1,6 +1,8 @@
 package github
 
 import (
+	"strings"
+
 	"github.com/google/go-github/v53/github"
 )
 
@@ -13,7 +15,7 @@ func (gh *gh_client) GetCommitContentDiff(commit *github.RepositoryCommit, org,
 }
 
 func (gh *gh_client) getContentDiff(sha, org, repo string) (string, error) {
-	diff, _, err := retryOnRateLimit(gh.log, func() (string, *github.Response, error) {
+	diff, _, err := retryOnRateLimit(gh.ctx, gh.log, func() (string, *github.Response, error) {
 		return gh.client.Repositories.GetCommitRaw(
 			gh.ctx,
 			org,
@@ -36,3 +38,55 @@ func (gh *gh_client) getContentDiff(sha, org, repo string) (string, error) {
 
 	return diff, nil
 }
+
+// SetDiffStats fills commit stats and files list based on the commit diff
+// the commits list endpoint does not return this data so it's cheaper to calculate it from the already downloaded diff
+func SetDiffStats(commit *github.RepositoryCommit, diff string) {
+	stats := &github.CommitStats{
+		Additions: github.Int(0),
+		Deletions: github.Int(0),
+		Total:     github.Int(0),
+	}
+	files := []*github.CommitFile{}
+
+	var current *github.CommitFile
+	inHunk := false
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			current = &github.CommitFile{
+				Filename:  github.String(parseDiffFilename(line)),
+				Additions: github.Int(0),
+				Deletions: github.Int(0),
+				Changes:   github.Int(0),
+			}
+			files = append(files, current)
+			inHunk = false
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case inHunk && current != nil && strings.HasPrefix(line, "+"):
+			*current.Additions++
+			*current.Changes++
+			*stats.Additions++
+			*stats.Total++
+		case inHunk && current != nil && strings.HasPrefix(line, "-"):
+			*current.Deletions++
+			*current.Changes++
+			*stats.Deletions++
+			*stats.Total++
+		}
+	}
+
+	commit.Stats = stats
+	commit.Files = files
+}
+
+// parseDiffFilename returns destination filename from the 'diff --git a/<file> b/<file>' line
+func parseDiffFilename(line string) string {
+	paths := strings.TrimPrefix(line, "diff --git ")
+	if i := strings.LastIndex(paths, " b/"); i >= 0 {
+		return paths[i+3:]
+	}
+
+	return paths
+}
@@ -3,6 +3,7 @@ package github
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -40,6 +41,54 @@ func Test_gh_client_GetFileDiffForPRs(t *testing.T) {
 	})
 }
 
+func TestSetDiffStats(t *testing.T) {
+	t.Run("parse diff", func(t *testing.T) {
+		diff := `diff --git a/main.go b/main.go
+index 1c1b51c..d880fdb 100644
+--- a/main.go
++++ b/main.go
+@@ -1,4 +1,5 @@
+ package main
+-import "fmt"
++import (
++	"fmt"
++)
+--- removed comment line
+diff --git a/assets/logo.png b/assets/logo.png
+new file mode 100644
+Binary files /dev/null and b/assets/logo.png differ
+diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1 +1 @@
+-old
++new
+\ No newline at end of file
+`
+		commit := &github.RepositoryCommit{}
+		SetDiffStats(commit, diff)
+
+		require.Equal(t, 4, commit.GetStats().GetAdditions())
+		require.Equal(t, 3, commit.GetStats().GetDeletions())
+		require.Equal(t, 7, commit.GetStats().GetTotal())
+		require.Len(t, commit.Files, 3)
+		require.Equal(t, "main.go", commit.Files[0].GetFilename())
+		require.Equal(t, 3, commit.Files[0].GetAdditions())
+		require.Equal(t, 2, commit.Files[0].GetDeletions())
+		require.Equal(t, "assets/logo.png", commit.Files[1].GetFilename())
+		require.Equal(t, 0, commit.Files[1].GetChanges())
+		require.Equal(t, "README.md", commit.Files[2].GetFilename())
+	})
+
+	t.Run("empty diff", func(t *testing.T) {
+		commit := &github.RepositoryCommit{}
+		SetDiffStats(commit, "")
+
+		require.Equal(t, 0, commit.GetStats().GetTotal())
+		require.Empty(t, commit.Files)
+	})
+}
+
 func fixLogger() *pterm.Logger {
 	log := &pterm.DefaultLogger
 	log.Writer = io.Discard
@@ -59,6 +108,9 @@ type testServerArgs struct {
 	branches []*github.Branch
 	commits  []*github.RepositoryCommit
 	repos    []*github.Repository
+	pulls    []*github.PullRequest
+	issues   []*github.Issue
+	orgs     []*github.Organization
 }
 
 func fixTestServer(t *testing.T, args *testServerArgs) *httptest.Server {
@@ -67,6 +119,37 @@ func fixTestServer(t *testing.T, args *testServerArgs) *httptest.Server {
 
 func handleTestRequest(t *testing.T, args *testServerArgs) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
+		// commit pull requests
+		if strings.HasSuffix(r.URL.Path, "/pulls") {
+			bytes, err := json.Marshal(args.pulls)
+			require.NoError(t, err)
+			_, _ = w.Write(bytes)
+			return
+		}
+
+		// issue
+		if strings.Contains(r.URL.String(), "/issues/") {
+			for _, issue := range args.issues {
+				if strings.HasSuffix(r.URL.Path, fmt.Sprintf("/issues/%d", issue.GetNumber())) {
+					bytes, err := json.Marshal(issue)
+					require.NoError(t, err)
+					_, _ = w.Write(bytes)
+					return
+				}
+			}
+
+			w.WriteHeader(404)
+			return
+		}
+
+		// user orgs
+		if strings.HasSuffix(r.URL.Path, "/orgs") {
+			bytes, err := json.Marshal(args.orgs)
+			require.NoError(t, err)
+			_, _ = w.Write(bytes)
+			return
+		}
+
 		// diff
 		if strings.Contains(r.URL.String(), "/commits/") {
 			_, _ = w.Write([]byte(diffMessage))
@@ -0,0 +1,49 @@
+package github
+
+import (
+	"fmt"
+
+	go_github "github.com/google/go-github/v53/github"
+)
+
+type orgList struct {
+	resp []*go_github.Organization
+}
+
+// ListUserOrgs returns names of public organizations of the user
+// returns organizations of the authenticated user ( also private memberships ) when username is empty
+func (gh *gh_client) ListUserOrgs(username string) ([]string, error) {
+	orgList := &orgList{
+		resp: []*go_github.Organization{},
+	}
+
+	err := listForPages(gh.listUserOrgsPageFunc(orgList, username))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orgs for user '%s': %s", username, err)
+	}
+
+	orgs := []string{}
+	for _, org := range orgList.resp {
+		orgs = append(orgs, org.GetLogin())
+	}
+
+	return orgs, nil
+}
+
+func (gh *gh_client) listUserOrgsPageFunc(dest *orgList, username string) pageListFunc {
+	return func(page int) (bool, error) {
+		perPage := 100
+		resp, _, err := retryOnRateLimit(gh.ctx, gh.log, func() ([]*go_github.Organization, *go_github.Response, error) {
+			return gh.client.Organizations.List(gh.ctx, username, &go_github.ListOptions{
+				Page:    page,
+				PerPage: perPage,
+			})
+		})
+		if err != nil {
+			return false, err
+		}
+
+		dest.resp = append(dest.resp, resp...)
+		return len(resp) == perPage, nil
+	}
+}
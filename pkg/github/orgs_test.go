@@ -0,0 +1,66 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	go_github "github.com/google/go-github/v53/github"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func Test_gh_client_ListUserOrgs(t *testing.T) {
+	t.Run("list orgs", func(t *testing.T) {
+		testOrgs := []string{
+			"test-org-1",
+			"test-org-2",
+		}
+
+		server := fixTestServer(t, &testServerArgs{
+			orgs: fixTestOrgs(testOrgs...),
+		})
+		defer server.Close()
+
+		gh := gh_client{
+			ctx:    context.Background(),
+			log:    fixLogger(),
+			client: fixTestClient(t, server),
+		}
+
+		orgs, err := gh.ListUserOrgs("test-user")
+
+		require.NoError(t, err)
+		require.Equal(t, testOrgs, orgs)
+	})
+
+	t.Run("client error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(404)
+		}))
+		defer server.Close()
+
+		gh := gh_client{
+			ctx:    context.Background(),
+			log:    fixLogger(),
+			client: fixTestClient(t, server),
+		}
+
+		orgs, err := gh.ListUserOrgs("test-user")
+
+		require.Error(t, err)
+		require.Empty(t, orgs)
+	})
+}
+
+func fixTestOrgs(logins ...string) []*go_github.Organization {
+	orgs := []*go_github.Organization{}
+	for _, login := range logins {
+		orgs = append(orgs, &go_github.Organization{
+			Login: ptr.To(login),
+		})
+	}
+
+	return orgs
+}
@@ -0,0 +1,114 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	go_github "github.com/google/go-github/v53/github"
+)
+
+// matches issues referenced with closing keywords ( submatches: org, repo, number )
+// the org and repo are empty for issues from the same repository
+// e.g.: "fixes #12", "Closes kyma-project/kyma#123"
+var ClosingIssueRegex = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s*:?\s+(?:([\w.-]+)/([\w.-]+))?#(\d+)`)
+
+type PullRequest struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	URL    string `json:"url"`
+	// issues closed by the pull request
+	Issues []Issue `json:"issues"`
+}
+
+type Issue struct {
+	Org    string `json:"org"`
+	Repo   string `json:"repo"`
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+}
+
+// ListCommitPullRequests returns pull requests associated with the commit and references to issues closed by them
+// closed issues are based on the closing keywords used in the pull request description
+// titles and URLs of issues are not fetched ( use GetIssue )
+func (gh *gh_client) ListCommitPullRequests(org, repo, sha string) ([]PullRequest, error) {
+	pulls, _, err := retryOnRateLimit(gh.ctx, gh.log, func() ([]*go_github.PullRequest, *go_github.Response, error) {
+		return gh.client.PullRequests.ListPullRequestsWithCommit(gh.ctx, org, repo, sha, nil)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests for commit '%s': %s", sha, err.Error())
+	}
+
+	pullRequests := []PullRequest{}
+	for _, pull := range pulls {
+		pullRequest := PullRequest{
+			Number: pull.GetNumber(),
+			Title:  pull.GetTitle(),
+			Body:   pull.GetBody(),
+			URL:    pull.GetHTMLURL(),
+			Issues: []Issue{},
+		}
+
+		pullRequest.Issues = append(pullRequest.Issues, parseClosingIssues(org, repo, pull.GetBody())...)
+
+		gh.log.Trace("got pull request for commit", gh.log.Args(
+			"org", org,
+			"repo", repo,
+			"sha", sha,
+			"number", pullRequest.Number,
+			"issues", len(pullRequest.Issues),
+		))
+
+		pullRequests = append(pullRequests, pullRequest)
+	}
+
+	return pullRequests, nil
+}
+
+// GetIssue returns the issue with its title and URL
+func (gh *gh_client) GetIssue(org, repo string, number int) (*Issue, error) {
+	issue, _, err := retryOnRateLimit(gh.ctx, gh.log, func() (*go_github.Issue, *go_github.Response, error) {
+		return gh.client.Issues.Get(gh.ctx, org, repo, number)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue '%s/%s#%d': %s", org, repo, number, err.Error())
+	}
+
+	return &Issue{
+		Org:    org,
+		Repo:   repo,
+		Number: number,
+		Title:  issue.GetTitle(),
+		URL:    issue.GetHTMLURL(),
+	}, nil
+}
+
+// parseClosingIssues returns unique issues referenced with closing keywords
+// issues without the org/repo prefix belong to the given repository
+func parseClosingIssues(org, repo, text string) []Issue {
+	issues := []Issue{}
+	found := map[string]struct{}{}
+	for _, match := range ClosingIssueRegex.FindAllStringSubmatch(text, -1) {
+		issue := Issue{
+			Org:  org,
+			Repo: repo,
+		}
+		if match[1] != "" {
+			issue.Org = match[1]
+			issue.Repo = match[2]
+		}
+		issue.Number, _ = strconv.Atoi(match[3])
+
+		key := fmt.Sprintf("%s/%s#%d", issue.Org, issue.Repo, issue.Number)
+		if _, ok := found[key]; ok {
+			continue
+		}
+
+		found[key] = struct{}{}
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
@@ -0,0 +1,111 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	go_github "github.com/google/go-github/v53/github"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func Test_gh_client_ListCommitPullRequests(t *testing.T) {
+	t.Run("list pull requests with closed issues references", func(t *testing.T) {
+		server := fixTestServer(t, &testServerArgs{
+			pulls: []*go_github.PullRequest{
+				{
+					Number:  ptr.To(123),
+					Title:   ptr.To("Add new feature"),
+					Body:    ptr.To("Fixes #12\nresolves other-org/other-repo#3\nfixes #12"),
+					HTMLURL: ptr.To("https://github.com/test-org/test-repo/pull/123"),
+				},
+			},
+		})
+		defer server.Close()
+
+		gh := gh_client{
+			ctx:    context.Background(),
+			log:    fixLogger(),
+			client: fixTestClient(t, server),
+		}
+
+		pullRequests, err := gh.ListCommitPullRequests("test-org", "test-repo", "sha1")
+		require.NoError(t, err)
+		require.Equal(t, []PullRequest{
+			{
+				Number: 123,
+				Title:  "Add new feature",
+				Body:   "Fixes #12\nresolves other-org/other-repo#3\nfixes #12",
+				URL:    "https://github.com/test-org/test-repo/pull/123",
+				Issues: []Issue{
+					{
+						Org:    "test-org",
+						Repo:   "test-repo",
+						Number: 12,
+					},
+					{
+						Org:    "other-org",
+						Repo:   "other-repo",
+						Number: 3,
+					},
+				},
+			},
+		}, pullRequests)
+	})
+
+	t.Run("client error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(404)
+		}))
+		defer server.Close()
+
+		gh := gh_client{
+			ctx:    context.Background(),
+			log:    fixLogger(),
+			client: fixTestClient(t, server),
+		}
+
+		pullRequests, err := gh.ListCommitPullRequests("test-org", "test-repo", "sha1")
+		require.ErrorContains(t, err, "failed to list pull requests for commit 'sha1'")
+		require.Nil(t, pullRequests)
+	})
+}
+
+func Test_gh_client_GetIssue(t *testing.T) {
+	server := fixTestServer(t, &testServerArgs{
+		issues: []*go_github.Issue{
+			{
+				Number:  ptr.To(12),
+				Title:   ptr.To("Feature request"),
+				HTMLURL: ptr.To("https://github.com/test-org/test-repo/issues/12"),
+			},
+		},
+	})
+	defer server.Close()
+
+	gh := gh_client{
+		ctx:    context.Background(),
+		log:    fixLogger(),
+		client: fixTestClient(t, server),
+	}
+
+	t.Run("get issue", func(t *testing.T) {
+		issue, err := gh.GetIssue("test-org", "test-repo", 12)
+		require.NoError(t, err)
+		require.Equal(t, &Issue{
+			Org:    "test-org",
+			Repo:   "test-repo",
+			Number: 12,
+			Title:  "Feature request",
+			URL:    "https://github.com/test-org/test-repo/issues/12",
+		}, issue)
+	})
+
+	t.Run("issue not found", func(t *testing.T) {
+		issue, err := gh.GetIssue("test-org", "test-repo", 99999)
+		require.ErrorContains(t, err, "failed to get issue 'test-org/test-repo#99999'")
+		require.Nil(t, issue)
+	})
+}
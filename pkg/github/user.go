@@ -3,7 +3,7 @@ package github
 import "github.com/google/go-github/v53/github"
 
 func (gh *gh_client) GetUserSignatures(username string) ([]string, error) {
-	user, _, err := retryOnRateLimit(gh.log, func() (*github.User, *github.Response, error) {
+	user, _, err := retryOnRateLimit(gh.ctx, gh.log, func() (*github.User, *github.Response, error) {
 		return gh.client.Users.Get(gh.ctx, username)
 	})
 	if err != nil {
@@ -2,6 +2,10 @@ package report
 
 import (
 	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"text/template"
 
 	"github.com/pPrecel/PKUP/internal/file"
@@ -15,33 +19,106 @@ approvalDate:
 {{.ApprovalDate}}
 
 result:
+{{if .GroupBy -}}
+{{range .Groups}}
+{{.Title}}:
+{{- range .Commits}}
+  - {{.Task}}{{if .DiffFile}} ({{.DiffFile}}){{end}}
+{{- end}}
+{{end}}
+{{- else -}}
+{{range .Result}}
+- {{ . -}}
+{{end}}
+{{- end}}
+`
+	// used when the locale is set
+	localizedTemplate = `{{.Labels.Title}}
+
+{{.Labels.Period}}:
+{{.PeriodFrom}} - {{.PeriodTill}}
+
+{{.Labels.ApprovalDate}}:
+{{.ApprovalDate}}
+
+{{.Labels.Results}}:
+{{if .GroupBy -}}
+{{range .Groups}}
+{{.Title}}:
+{{- range .Commits}}
+  - {{.Task}}{{if .DiffFile}} ({{.DiffFile}}){{end}}
+{{- end}}
+{{end}}
+{{- else -}}
 {{range .Result}}
 - {{ . -}}
 {{end}}
+{{- end}}
 `
 )
 
+// helper functions available in the text templates
+var textTemplateFuncs = template.FuncMap{
+	"join":      strings.Join,
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+	"trim":      strings.TrimSpace,
+	"repeat":    strings.Repeat,
+	"firstLine": firstLine,
+	"add":       func(a, b int) int { return a + b },
+	"default": func(def, value string) string {
+		if value == "" {
+			return def
+		}
+		return value
+	},
+	"escapeMarkdown": escapeMarkdown,
+}
+
 type defaultRenderer struct {
-	template string
+	template *template.Template
 }
 
 func newDefault() *defaultRenderer {
 	return &defaultRenderer{
-		template: defaultTemplate,
+		template: template.Must(template.New("report.txt").Funcs(textTemplateFuncs).Parse(defaultTemplate)),
 	}
 }
 
-func (dr *defaultRenderer) RenderToFile(dir, filename string, values Values) error {
-	tmpl, err := template.New(filename).Parse(dr.template)
+func newLocalizedDefault() *defaultRenderer {
+	return &defaultRenderer{
+		template: template.Must(template.New("report.txt").Funcs(textTemplateFuncs).Parse(localizedTemplate)),
+	}
+}
+
+// newFromTextTemplate returns renderer based on the user go template file
+// the template is parsed once and can be rendered many times ( e.g. for every locale )
+func newFromTextTemplate(path string) (*defaultRenderer, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to load text template: %s", err.Error())
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Funcs(textTemplateFuncs).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse text template: %s", err.Error())
 	}
 
+	return &defaultRenderer{
+		template: tmpl,
+	}, nil
+}
+
+func (dr *defaultRenderer) RenderToFile(dir, filename string, values Values) error {
 	buf := bytes.NewBuffer(nil)
-	err = tmpl.Execute(buf, values)
+	err := dr.template.Execute(buf, values)
 	if err != nil {
 		return err
 	}
 
 	return file.Create(dir, filename, buf.String())
 }
+
+func firstLine(text string) string {
+	return strings.Split(text, "\n")[0]
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"encoding/json"
+	"path"
+
+	"github.com/pPrecel/PKUP/internal/file"
+	"gopkg.in/yaml.v3"
+)
+
+// ExportSchemaVersion is the version of the Export document
+// it's increased on every change that removes or renames any field
+const ExportSchemaVersion = "1"
+
+// Export is the machine-readable document saved by the json and yaml formats
+type Export struct {
+	// version of the document schema ( see ExportSchemaVersion )
+	SchemaVersion string `json:"schemaVersion" yaml:"schemaVersion"`
+	// all values used to render reports
+	Values `yaml:",inline"`
+	// flat list of all commits from the Repos
+	Commits []CommitValues `json:"commits" yaml:"commits"`
+}
+
+type exportRenderer struct {
+	marshal func(interface{}) ([]byte, error)
+}
+
+func newJSONExport() *exportRenderer {
+	return &exportRenderer{
+		marshal: func(v interface{}) ([]byte, error) {
+			return json.MarshalIndent(v, "", "  ")
+		},
+	}
+}
+
+func newYAMLExport() *exportRenderer {
+	return &exportRenderer{
+		marshal: yaml.Marshal,
+	}
+}
+
+func (er *exportRenderer) RenderToFile(dir, filename string, values Values) error {
+	data, err := er.marshal(buildExport(values))
+	if err != nil {
+		return err
+	}
+
+	return file.WriteAtomic(path.Join(dir, filename), data)
+}
+
+func buildExport(values Values) Export {
+	return Export{
+		SchemaVersion: ExportSchemaVersion,
+		Values:        values,
+		Commits:       allCommits(values.Repos),
+	}
+}
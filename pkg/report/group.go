@@ -0,0 +1,156 @@
+package report
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pPrecel/PKUP/pkg/github"
+)
+
+const (
+	GroupByRepo        = "repo"
+	GroupByPullRequest = "pr"
+	GroupByIssue       = "issue"
+	GroupByScope       = "scope"
+)
+
+// list of all supported grouping options
+var GroupByOptions = []string{
+	GroupByRepo,
+	GroupByPullRequest,
+	GroupByIssue,
+	GroupByScope,
+}
+
+var (
+	// e.g.: "add new feature (#123)"
+	squashPRRegex = regexp.MustCompile(`\(#(\d+)\)\s*$`)
+	// e.g.: "Merge pull request #123 from pPrecel/branch"
+	mergePRRegex = regexp.MustCompile(`^Merge pull request #(\d+)`)
+	// e.g.: "PROJ-123"
+	issueKeyRegex = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-\d+\b`)
+	// e.g.: "feat(serverless): add new feature"
+	scopeRegex = regexp.MustCompile(`^\w+\(([^)]+)\)!?:`)
+)
+
+type GroupValues struct {
+	Title   string         `json:"title" yaml:"title"`
+	Commits []CommitValues `json:"commits" yaml:"commits"`
+	Stats   Stats          `json:"stats" yaml:"stats"`
+}
+
+func ValidateGroupBy(groupBy string) error {
+	if groupBy == "" {
+		return nil
+	}
+
+	for _, g := range GroupByOptions {
+		if g == groupBy {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported group by option '%s' (supported: %s)", groupBy, strings.Join(GroupByOptions, ", "))
+}
+
+// buildReportGroups splits commits into groups in order of their first occurrence
+// commits are grouped by repository when groupBy is empty
+// commits that can't be classified are put in the last group with the otherTitle
+func buildReportGroups(repos []RepoValues, groupBy, otherTitle string) []GroupValues {
+	keyFunc := groupKeyFunc(groupBy)
+
+	groups := []GroupValues{}
+	indexes := map[string]int{}
+	other := GroupValues{Title: otherTitle}
+	for _, repo := range repos {
+		for _, commit := range repo.Commits {
+			key := keyFunc(commit)
+			if key == "" {
+				other.Commits = append(other.Commits, commit)
+				continue
+			}
+
+			i, ok := indexes[key]
+			if !ok {
+				i = len(groups)
+				indexes[key] = i
+				groups = append(groups, GroupValues{Title: key})
+			}
+
+			groups[i].Commits = append(groups[i].Commits, commit)
+		}
+	}
+
+	if len(other.Commits) > 0 {
+		groups = append(groups, other)
+	}
+
+	for i := range groups {
+		groups[i].Stats = sumCommitStats(groups[i].Commits)
+	}
+
+	return groups
+}
+
+func groupKeyFunc(groupBy string) func(CommitValues) string {
+	switch groupBy {
+	case GroupByPullRequest:
+		return pullRequestKey
+	case GroupByIssue:
+		return issueKey
+	case GroupByScope:
+		return scopeKey
+	default:
+		return repoKey
+	}
+}
+
+func repoKey(commit CommitValues) string {
+	return fmt.Sprintf("%s/%s", commit.Org, commit.Repo)
+}
+
+func pullRequestKey(commit CommitValues) string {
+	if len(commit.PullRequests) > 0 {
+		// use pull request found by the enrichment
+		return fmt.Sprintf("%s/%s#%d", commit.Org, commit.Repo, commit.PullRequests[0].Number)
+	}
+
+	for _, regex := range []*regexp.Regexp{squashPRRegex, mergePRRegex} {
+		if match := regex.FindStringSubmatch(commit.Message); match != nil {
+			return fmt.Sprintf("%s/%s#%s", commit.Org, commit.Repo, match[1])
+		}
+	}
+
+	return ""
+}
+
+func issueKey(commit CommitValues) string {
+	for _, pr := range commit.PullRequests {
+		if len(pr.Issues) > 0 {
+			// use issue found by the enrichment
+			issue := pr.Issues[0]
+			return fmt.Sprintf("%s/%s#%d", issue.Org, issue.Repo, issue.Number)
+		}
+	}
+
+	message := commit.Message + "\n" + commit.Body
+	if match := github.ClosingIssueRegex.FindStringSubmatch(message); match != nil {
+		if match[1] == "" {
+			// issue from the same repository
+			return fmt.Sprintf("%s/%s#%s", commit.Org, commit.Repo, match[3])
+		}
+
+		return fmt.Sprintf("%s/%s#%s", match[1], match[2], match[3])
+	}
+
+	return issueKeyRegex.FindString(message)
+}
+
+func scopeKey(commit CommitValues) string {
+	if match := scopeRegex.FindStringSubmatch(commit.Message); match != nil {
+		return match[1]
+	}
+
+	return ""
+}
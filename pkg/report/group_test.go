@@ -0,0 +1,167 @@
+package report
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testGroupRepos = []RepoValues{
+		{
+			Org:  "test-org",
+			Repo: "test-repo",
+			Commits: []CommitValues{
+				{Org: "test-org", Repo: "test-repo", SHA: "sha1", Message: "feat(api): add endpoint (#12)", Body: "Fixes #5", Stats: Stats{Commits: 1, Repos: 1, Additions: 10}},
+				{Org: "test-org", Repo: "test-repo", SHA: "sha2", Message: "fix(api): typo (#12)", Body: "PROJ-44", Stats: Stats{Commits: 1, Repos: 1, Additions: 2, Deletions: 1}},
+				{Org: "test-org", Repo: "test-repo", SHA: "sha3", Message: "Merge pull request #13 from user/branch", Stats: Stats{Commits: 1, Repos: 1}},
+			},
+		},
+		{
+			Org:  "test-org",
+			Repo: "other-repo",
+			Commits: []CommitValues{
+				{Org: "test-org", Repo: "other-repo", SHA: "sha4", Message: "docs: update readme", Body: "closes test-org/test-repo#5", Stats: Stats{Commits: 1, Repos: 1, Deletions: 3, FilesChanged: 1}},
+			},
+		},
+	}
+)
+
+func Test_buildReportGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		groupBy string
+		want    map[string][]string
+		order   []string
+	}{
+		{
+			name:    "group by repo",
+			groupBy: GroupByRepo,
+			order:   []string{"test-org/test-repo", "test-org/other-repo"},
+			want: map[string][]string{
+				"test-org/test-repo":  {"sha1", "sha2", "sha3"},
+				"test-org/other-repo": {"sha4"},
+			},
+		},
+		{
+			name:    "group by repo when empty",
+			groupBy: "",
+			order:   []string{"test-org/test-repo", "test-org/other-repo"},
+			want: map[string][]string{
+				"test-org/test-repo":  {"sha1", "sha2", "sha3"},
+				"test-org/other-repo": {"sha4"},
+			},
+		},
+		{
+			name:    "group by pull request",
+			groupBy: GroupByPullRequest,
+			order:   []string{"test-org/test-repo#12", "test-org/test-repo#13", "other"},
+			want: map[string][]string{
+				"test-org/test-repo#12": {"sha1", "sha2"},
+				"test-org/test-repo#13": {"sha3"},
+				"other":                 {"sha4"},
+			},
+		},
+		{
+			name:    "group by issue",
+			groupBy: GroupByIssue,
+			order:   []string{"test-org/test-repo#5", "PROJ-44", "other"},
+			want: map[string][]string{
+				"test-org/test-repo#5": {"sha1", "sha4"},
+				"PROJ-44":              {"sha2"},
+				"other":                {"sha3"},
+			},
+		},
+		{
+			name:    "group by scope",
+			groupBy: GroupByScope,
+			order:   []string{"api", "other"},
+			want: map[string][]string{
+				"api":   {"sha1", "sha2"},
+				"other": {"sha3", "sha4"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups := buildReportGroups(testGroupRepos, tt.groupBy, "other")
+
+			order := []string{}
+			for _, group := range groups {
+				order = append(order, group.Title)
+
+				shas := []string{}
+				for _, commit := range group.Commits {
+					shas = append(shas, commit.SHA)
+				}
+				require.Equal(t, tt.want[group.Title], shas)
+				require.Equal(t, len(shas), group.Stats.Commits)
+			}
+			require.Equal(t, tt.order, order)
+		})
+	}
+
+	t.Run("sum group stats", func(t *testing.T) {
+		groups := buildReportGroups(testGroupRepos, GroupByIssue, "other")
+
+		require.Equal(t, Stats{Commits: 2, Repos: 2, Additions: 10, Deletions: 3, FilesChanged: 1}, groups[0].Stats)
+		require.Equal(t, Stats{Commits: 1, Repos: 1, Additions: 2, Deletions: 1}, groups[1].Stats)
+	})
+
+	t.Run("group by enriched pull requests and issues", func(t *testing.T) {
+		repos := []RepoValues{
+			{
+				Org:  "test-org",
+				Repo: "test-repo",
+				Commits: []CommitValues{
+					{Org: "test-org", Repo: "test-repo", SHA: "sha1", Message: "add endpoint", PullRequests: []PullRequestValues{
+						{Number: 21, Issues: []IssueValues{{Org: "other-org", Repo: "other-repo", Number: 7}}},
+					}},
+					{Org: "test-org", Repo: "test-repo", SHA: "sha2", Message: "fix typo (#12)"},
+				},
+			},
+		}
+
+		groups := buildReportGroups(repos, GroupByPullRequest, "other")
+		require.Equal(t, "test-org/test-repo#21", groups[0].Title)
+		require.Equal(t, "test-org/test-repo#12", groups[1].Title)
+
+		groups = buildReportGroups(repos, GroupByIssue, "other")
+		require.Equal(t, "other-org/other-repo#7", groups[0].Title)
+		require.Equal(t, "other", groups[1].Title)
+	})
+}
+
+func TestRender_grouped(t *testing.T) {
+	t.Run("render grouped default report", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		_, err := Render(Options{
+			OutputDir:  tmpDir,
+			GroupBy:    GroupByPullRequest,
+			PeriodFrom: time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC),
+			PeriodTill: time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC),
+			Results:    testResults,
+		})
+		require.NoError(t, err)
+
+		body, err := os.ReadFile(path.Join(tmpDir, "report.txt"))
+		require.NoError(t, err)
+		require.Contains(t, string(body), "result:\n\n"+
+			"test-org/test-repo#123:\n"+
+			"  - test PR 1 (#123) (test-org_test-repo_sha1.diff)\n\n"+
+			"test-org/test-repo#124:\n"+
+			"  - test <PR> 2 (#124)\n")
+	})
+
+	t.Run("unsupported group by option", func(t *testing.T) {
+		_, err := Render(Options{
+			OutputDir: t.TempDir(),
+			GroupBy:   "author",
+		})
+		require.ErrorContains(t, err, "unsupported group by option 'author'")
+	})
+}
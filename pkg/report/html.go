@@ -0,0 +1,76 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+
+	"github.com/pPrecel/PKUP/internal/file"
+)
+
+const (
+	htmlTemplate = `<!DOCTYPE html>
+<html{{if .Locale}} lang="{{.Locale}}"{{end}}>
+<head>
+<meta charset="utf-8">
+<title>{{.Labels.Title}} {{.PeriodFrom}} - {{.PeriodTill}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #24292f; }
+table { border-collapse: collapse; }
+td { padding: 0.2em 1em 0.2em 0; }
+h2 small { color: #57606a; font-weight: normal; }
+code { color: #57606a; }
+</style>
+</head>
+<body>
+<h1>{{.Labels.Title}}</h1>
+<table>
+<tr><td><b>{{.Labels.Period}}</b></td><td>{{.PeriodFrom}} - {{.PeriodTill}}</td></tr>
+<tr><td><b>{{.Labels.ApprovalDate}}</b></td><td>{{.ApprovalDate}}</td></tr>
+<tr><td><b>{{.Labels.Commits}}</b></td><td>{{summary .Labels.CommitsSummary .Stats.Commits .Stats.Repos}}</td></tr>
+<tr><td><b>{{.Labels.Changes}}</b></td><td>{{summary .Labels.ChangesSummary .Stats.Additions .Stats.Deletions .Stats.FilesChanged}}</td></tr>
+</table>
+{{- range .Groups}}
+<h2>{{.Title}} <small>{{summary $.Labels.GroupSummary .Stats.Commits .Stats.Additions .Stats.Deletions .Stats.FilesChanged}}</small></h2>
+<ul>
+{{- range .Commits}}
+<li>{{if .URL}}<a href="{{.URL}}">{{.Task}}</a>{{else}}{{.Task}}{{end}}{{if .DiffFile}} (<code>{{.DiffFile}}</code>){{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`
+)
+
+type htmlRenderer struct {
+	template string
+}
+
+func newHTML() *htmlRenderer {
+	return &htmlRenderer{
+		template: htmlTemplate,
+	}
+}
+
+func (hr *htmlRenderer) RenderToFile(dir, filename string, values Values) error {
+	tmpl, err := template.New(filename).Funcs(template.FuncMap{
+		"summary": htmlSummary,
+	}).Parse(hr.template)
+	if err != nil {
+		return err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = tmpl.Execute(buf, values)
+	if err != nil {
+		return err
+	}
+
+	return file.Create(dir, filename, buf.String())
+}
+
+// htmlSummary formats summary label without escaping the '+' sign
+func htmlSummary(format string, args ...interface{}) template.HTML {
+	return template.HTML(template.HTMLEscapeString(fmt.Sprintf(format, args...)))
+}
@@ -0,0 +1,162 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	LocalePL = "pl"
+	LocaleEN = "en"
+)
+
+// list of all supported locales
+var Locales = []string{
+	LocalePL,
+	LocaleEN,
+}
+
+type Locale struct {
+	// layout used to format dates ( e.g. "02.01.2006" )
+	DateFormat string
+	// month names used in dates ( e.g. "19 września 2023" )
+	DateMonths [12]string
+	// month names used alone ( e.g. "wrzesień 2023" )
+	Months [12]string
+	Labels Labels
+}
+
+// Labels contains built-in texts used by renderers
+// fields ending with 'Summary' are fmt formats
+type Labels struct {
+	Title             string `json:"title" yaml:"title"`
+	Period            string `json:"period" yaml:"period"`
+	ApprovalDate      string `json:"approvalDate" yaml:"approvalDate"`
+	Results           string `json:"results" yaml:"results"`
+	Commits           string `json:"commits" yaml:"commits"`
+	Changes           string `json:"changes" yaml:"changes"`
+	Other             string `json:"other" yaml:"other"`
+	Employee          string `json:"employee" yaml:"employee"`
+	JobTitle          string `json:"jobTitle" yaml:"jobTitle"`
+	Department        string `json:"department" yaml:"department"`
+	Manager           string `json:"manager" yaml:"manager"`
+	Contribution      string `json:"contribution" yaml:"contribution"`
+	Number            string `json:"number" yaml:"number"`
+	Description       string `json:"description" yaml:"description"`
+	Artifact          string `json:"artifact" yaml:"artifact"`
+	EmployeeSignature string `json:"employeeSignature" yaml:"employeeSignature"`
+	ManagerSignature  string `json:"managerSignature" yaml:"managerSignature"`
+	// args: commits, repos
+	CommitsSummary string `json:"commitsSummary" yaml:"commitsSummary"`
+	// args: additions, deletions, files
+	ChangesSummary string `json:"changesSummary" yaml:"changesSummary"`
+	// args: commits, additions, deletions, files
+	GroupSummary string `json:"groupSummary" yaml:"groupSummary"`
+}
+
+var englishLabels = Labels{
+	Title:             "PKUP report",
+	Period:            "Period",
+	ApprovalDate:      "Approval date",
+	Results:           "Results",
+	Commits:           "Commits",
+	Changes:           "Changes",
+	Other:             "other",
+	Employee:          "Employee",
+	JobTitle:          "Job title",
+	Department:        "Department",
+	Manager:           "Manager",
+	Contribution:      "Contribution",
+	Number:            "No.",
+	Description:       "Description",
+	Artifact:          "Artifact",
+	EmployeeSignature: "Employee's signature",
+	ManagerSignature:  "Manager's signature",
+	CommitsSummary:    "%d in %d repositories",
+	ChangesSummary:    "+%d/-%d lines in %d files",
+	GroupSummary:      "%d commits, +%d/-%d lines in %d files",
+}
+
+var englishMonths = [12]string{
+	"January", "February", "March", "April", "May", "June",
+	"July", "August", "September", "October", "November", "December",
+}
+
+var locales = map[string]Locale{
+	// default locale keeps formats used before localization was introduced
+	"": {
+		DateFormat: PeriodFormat,
+		DateMonths: englishMonths,
+		Months:     englishMonths,
+		Labels:     englishLabels,
+	},
+	LocaleEN: {
+		DateFormat: "2006-01-02",
+		DateMonths: englishMonths,
+		Months:     englishMonths,
+		Labels:     englishLabels,
+	},
+	LocalePL: {
+		DateFormat: PeriodFormat,
+		DateMonths: [12]string{
+			"stycznia", "lutego", "marca", "kwietnia", "maja", "czerwca",
+			"lipca", "sierpnia", "września", "października", "listopada", "grudnia",
+		},
+		Months: [12]string{
+			"styczeń", "luty", "marzec", "kwiecień", "maj", "czerwiec",
+			"lipiec", "sierpień", "wrzesień", "październik", "listopad", "grudzień",
+		},
+		Labels: Labels{
+			Title:             "Raport PKUP",
+			Period:            "Okres",
+			ApprovalDate:      "Data zatwierdzenia",
+			Results:           "Wyniki",
+			Commits:           "Commity",
+			Changes:           "Zmiany",
+			Other:             "inne",
+			Employee:          "Pracownik",
+			JobTitle:          "Stanowisko",
+			Department:        "Dział",
+			Manager:           "Przełożony",
+			Contribution:      "Wkład",
+			Number:            "Lp.",
+			Description:       "Opis",
+			Artifact:          "Artefakt",
+			EmployeeSignature: "Podpis pracownika",
+			ManagerSignature:  "Podpis przełożonego",
+			CommitsSummary:    "%d (liczba repozytoriów: %d)",
+			ChangesSummary:    "+%d/-%d linii (liczba plików: %d)",
+			GroupSummary:      "liczba commitów: %d, +%d/-%d linii (liczba plików: %d)",
+		},
+	},
+}
+
+func ValidateLocale(locale string) error {
+	for _, l := range Locales {
+		if l == locale {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported locale '%s' (supported: %s)", locale, strings.Join(Locales, ", "))
+}
+
+func getLocale(locale string) Locale {
+	return locales[locale]
+}
+
+// FormatDate formats date using the locale date format
+func (l Locale) FormatDate(t time.Time) string {
+	return t.Format(l.DateFormat)
+}
+
+// FormatLongDate formats date with the month name ( e.g. "19 września 2023" )
+func (l Locale) FormatLongDate(t time.Time) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), l.DateMonths[t.Month()-1], t.Year())
+}
+
+// FormatMonth formats month with year ( e.g. "wrzesień 2023" )
+func (l Locale) FormatMonth(t time.Time) string {
+	return fmt.Sprintf("%s %d", l.Months[t.Month()-1], t.Year())
+}
@@ -0,0 +1,78 @@
+package report
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocale_FormatLongDate(t *testing.T) {
+	date := time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC)
+
+	require.Equal(t, "19 września 2023", getLocale(LocalePL).FormatLongDate(date))
+	require.Equal(t, "wrzesień 2023", getLocale(LocalePL).FormatMonth(date))
+	require.Equal(t, "19.09.2023", getLocale(LocalePL).FormatDate(date))
+	require.Equal(t, "19 September 2023", getLocale(LocaleEN).FormatLongDate(date))
+	require.Equal(t, "2023-09-19", getLocale(LocaleEN).FormatDate(date))
+}
+
+func TestRender_locales(t *testing.T) {
+	t.Run("render many language variants", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		_, err := Render(Options{
+			OutputDir:  tmpDir,
+			Formats:    []string{FormatTxt, FormatMarkdown},
+			Locales:    []string{LocalePL, LocaleEN},
+			PeriodFrom: time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC),
+			PeriodTill: time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC),
+			Results:    testResults,
+		})
+		require.NoError(t, err)
+		require.NoFileExists(t, path.Join(tmpDir, "report.txt"))
+
+		body, err := os.ReadFile(path.Join(tmpDir, "report_pl.txt"))
+		require.NoError(t, err)
+		require.Contains(t, string(body), "Raport PKUP\n\nOkres:\n19.09.2023 - 18.10.2023\n\nData zatwierdzenia:\n19.10.2023\n\nWyniki:\n")
+
+		body, err = os.ReadFile(path.Join(tmpDir, "report_pl.md"))
+		require.NoError(t, err)
+		require.Contains(t, string(body), "**Commity:** 2 (liczba repozytoriów: 1)")
+
+		body, err = os.ReadFile(path.Join(tmpDir, "report_en.txt"))
+		require.NoError(t, err)
+		require.Contains(t, string(body), "PKUP report\n\nPeriod:\n2023-09-19 - 2023-10-18\n")
+
+		require.FileExists(t, path.Join(tmpDir, "report_en.md"))
+	})
+
+	t.Run("render single language variant", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		_, err := Render(Options{
+			OutputDir:  tmpDir,
+			Formats:    []string{FormatPDF},
+			Locales:    []string{LocalePL},
+			PeriodFrom: time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC),
+			PeriodTill: time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC),
+			Results:    testResults,
+		})
+		require.NoError(t, err)
+
+		body, err := os.ReadFile(path.Join(tmpDir, "report.pdf"))
+		require.NoError(t, err)
+		// 'ł' and 'ż' are encoded using custom font encoding
+		require.Contains(t, string(body), "(Podpis prze\xa6o\xbconego) Tj")
+	})
+
+	t.Run("unsupported locale", func(t *testing.T) {
+		_, err := Render(Options{
+			OutputDir: t.TempDir(),
+			Locales:   []string{"de"},
+		})
+		require.ErrorContains(t, err, "unsupported locale 'de'")
+	})
+}
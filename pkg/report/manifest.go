@@ -0,0 +1,112 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	go_github "github.com/google/go-github/v53/github"
+	"github.com/pPrecel/PKUP/internal/file"
+)
+
+const (
+	// name of the file saved next to reports and used to render them again without calling GitHub
+	ManifestFilename = "pkup-manifest.json"
+	// version of the Manifest document
+	ManifestSchemaVersion = "1"
+)
+
+// Manifest contains everything needed to render reports again
+type Manifest struct {
+	SchemaVersion string            `json:"schemaVersion"`
+	TemplatePath  string            `json:"templatePath,omitempty"`
+	Formats       []string          `json:"formats,omitempty"`
+	PDFLayoutPath string            `json:"pdfLayoutPath,omitempty"`
+	GroupBy       string            `json:"groupBy,omitempty"`
+	Locales       []string          `json:"locales,omitempty"`
+	PeriodFrom    time.Time         `json:"periodFrom"`
+	PeriodTill    time.Time         `json:"periodTill"`
+	Results       []Result          `json:"results"`
+	CustomValues  map[string]string `json:"customValues,omitempty"`
+	Validation    ValidationOptions `json:"validation"`
+}
+
+// SaveManifest saves options used to render reports in the output dir
+func SaveManifest(opts Options) error {
+	manifest := Manifest{
+		SchemaVersion: ManifestSchemaVersion,
+		TemplatePath:  opts.TemplatePath,
+		Formats:       opts.Formats,
+		PDFLayoutPath: opts.PDFLayoutPath,
+		GroupBy:       opts.GroupBy,
+		Locales:       opts.Locales,
+		PeriodFrom:    opts.PeriodFrom,
+		PeriodTill:    opts.PeriodTill,
+		Results:       opts.Results,
+		CustomValues:  opts.CustomValues,
+		Validation:    opts.Validation,
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %s", err.Error())
+	}
+
+	return file.WriteAtomic(path.Join(opts.OutputDir, ManifestFilename), data)
+}
+
+// ReadManifest reads options saved in the dir
+// commits with removed .diff artifacts are skipped
+func ReadManifest(dir string) (*Options, error) {
+	data, err := os.ReadFile(path.Join(dir, ManifestFilename))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %s", err.Error())
+	}
+
+	manifest := Manifest{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %s", err.Error())
+	}
+
+	if manifest.SchemaVersion != ManifestSchemaVersion {
+		return nil, fmt.Errorf("unsupported manifest version '%s' (supported: %s)", manifest.SchemaVersion, ManifestSchemaVersion)
+	}
+
+	for i := range manifest.Results {
+		manifest.Results[i].CommitList.Commits = filterRemovedArtifacts(dir, manifest.Results[i])
+	}
+
+	return &Options{
+		OutputDir:     dir,
+		TemplatePath:  manifest.TemplatePath,
+		Formats:       manifest.Formats,
+		PDFLayoutPath: manifest.PDFLayoutPath,
+		GroupBy:       manifest.GroupBy,
+		Locales:       manifest.Locales,
+		PeriodFrom:    manifest.PeriodFrom,
+		PeriodTill:    manifest.PeriodTill,
+		Results:       manifest.Results,
+		CustomValues:  manifest.CustomValues,
+		Validation:    manifest.Validation,
+	}, nil
+}
+
+func filterRemovedArtifacts(dir string, result Result) []*go_github.RepositoryCommit {
+	commits := []*go_github.RepositoryCommit{}
+	for _, commit := range result.CommitList.Commits {
+		filename := diffFilename(result.Org, result.Repo, commit)
+		if filename == "" {
+			// artifacts are not saved for empty diffs
+			commits = append(commits, commit)
+			continue
+		}
+
+		if _, err := os.Stat(path.Join(dir, filename)); err == nil {
+			commits = append(commits, commit)
+		}
+	}
+
+	return commits
+}
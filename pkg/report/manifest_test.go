@@ -0,0 +1,86 @@
+package report
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadManifest(t *testing.T) {
+	fixManifest := func(t *testing.T) (string, Options) {
+		tmpDir := t.TempDir()
+		opts := Options{
+			OutputDir:  tmpDir,
+			Formats:    []string{FormatMarkdown},
+			GroupBy:    GroupByPullRequest,
+			Locales:    []string{LocalePL},
+			PeriodFrom: time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC),
+			PeriodTill: time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC),
+			Results:    testResults,
+			CustomValues: map[string]string{
+				"pkupGenEmployeesName": "John Wick",
+			},
+			Validation: ValidationOptions{
+				Mode: ValidationModeFail,
+			},
+		}
+		require.NoError(t, SaveManifest(opts))
+
+		return tmpDir, opts
+	}
+
+	t.Run("read options and skip removed artifacts", func(t *testing.T) {
+		tmpDir, opts := fixManifest(t)
+
+		opts2, err := ReadManifest(tmpDir)
+		require.NoError(t, err)
+		require.Equal(t, opts.Formats, opts2.Formats)
+		require.Equal(t, opts.GroupBy, opts2.GroupBy)
+		require.Equal(t, opts.Locales, opts2.Locales)
+		require.Equal(t, opts.CustomValues, opts2.CustomValues)
+		require.Equal(t, opts.Validation, opts2.Validation)
+		require.True(t, opts.PeriodFrom.Equal(opts2.PeriodFrom))
+		require.Len(t, opts2.Results, 2)
+
+		// sha1 diff file does not exist and sha2 has no artifact because of the empty diff
+		commits := opts2.Results[0].CommitList.Commits
+		require.Len(t, commits, 1)
+		require.Equal(t, "sha2", commits[0].GetSHA())
+	})
+
+	t.Run("keep commits with artifacts", func(t *testing.T) {
+		tmpDir, _ := fixManifest(t)
+		require.NoError(t, os.WriteFile(path.Join(tmpDir, "test-org_test-repo_sha1.diff"), []byte("diff"), os.ModePerm))
+
+		opts, err := ReadManifest(tmpDir)
+		require.NoError(t, err)
+
+		commits := opts.Results[0].CommitList.Commits
+		require.Len(t, commits, 2)
+		require.Equal(t, 10, commits[0].GetStats().GetAdditions())
+		require.Len(t, commits[0].Files, 2)
+
+		_, err = Render(*opts)
+		require.NoError(t, err)
+
+		body, err := os.ReadFile(path.Join(tmpDir, "report.md"))
+		require.NoError(t, err)
+		require.Contains(t, string(body), "test-org/test-repo#123")
+	})
+
+	t.Run("missing manifest", func(t *testing.T) {
+		_, err := ReadManifest(t.TempDir())
+		require.ErrorContains(t, err, "failed to read manifest")
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		require.NoError(t, os.WriteFile(path.Join(tmpDir, ManifestFilename), []byte(`{"schemaVersion": "0"}`), os.ModePerm))
+
+		_, err := ReadManifest(tmpDir)
+		require.ErrorContains(t, err, "unsupported manifest version '0'")
+	})
+}
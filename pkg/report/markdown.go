@@ -0,0 +1,68 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/pPrecel/PKUP/internal/file"
+)
+
+const (
+	markdownTemplate = `# {{.Labels.Title}}
+
+**{{.Labels.Period}}:** {{.PeriodFrom}} - {{.PeriodTill}}  
+**{{.Labels.ApprovalDate}}:** {{.ApprovalDate}}  
+**{{.Labels.Commits}}:** {{printf .Labels.CommitsSummary .Stats.Commits .Stats.Repos}}  
+**{{.Labels.Changes}}:** {{printf .Labels.ChangesSummary .Stats.Additions .Stats.Deletions .Stats.FilesChanged}}
+{{range .Groups}}
+## {{escape .Title}}
+
+_{{printf $.Labels.GroupSummary .Stats.Commits .Stats.Additions .Stats.Deletions .Stats.FilesChanged}}_
+{{range .Commits}}
+- {{if .URL}}[{{escape .Task}}]({{.URL}}){{else}}{{escape .Task}}{{end}}{{if .DiffFile}} ` + "(`{{.DiffFile}}`)" + `{{end}}
+{{- end}}
+{{end}}`
+)
+
+type markdownRenderer struct {
+	template string
+}
+
+func newMarkdown() *markdownRenderer {
+	return &markdownRenderer{
+		template: markdownTemplate,
+	}
+}
+
+func (mr *markdownRenderer) RenderToFile(dir, filename string, values Values) error {
+	tmpl, err := template.New(filename).Funcs(template.FuncMap{
+		"escape": escapeMarkdown,
+	}).Parse(mr.template)
+	if err != nil {
+		return err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = tmpl.Execute(buf, values)
+	if err != nil {
+		return err
+	}
+
+	return file.Create(dir, filename, buf.String())
+}
+
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+)
+
+func escapeMarkdown(text string) string {
+	return markdownReplacer.Replace(text)
+}
@@ -0,0 +1,111 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/pPrecel/PKUP/internal/file"
+)
+
+// files of the OpenDocument package that can contain placeholders
+// styles.xml contains headers and footers
+var odtContentFiles = []string{
+	"content.xml",
+	"styles.xml",
+}
+
+type odtRenderer struct {
+	tmplPath string
+}
+
+func newODTFromTemplate(path string) *odtRenderer {
+	return &odtRenderer{
+		tmplPath: path,
+	}
+}
+
+func (ot *odtRenderer) RenderToFile(dir, filename string, values Values) error {
+	r, err := zip.OpenReader(ot.tmplPath)
+	if err != nil {
+		return fmt.Errorf("failed to load odt template: %s", err.Error())
+	}
+	defer r.Close()
+
+	placeholders := buildPlaceholders(values)
+
+	buf := bytes.NewBuffer(nil)
+	w := zip.NewWriter(buf)
+	for _, f := range r.File {
+		if !isODTContentFile(f.Name) {
+			// copy file without decompressing it to keep the 'mimetype' file stored as the first one
+			if err := w.Copy(f); err != nil {
+				return err
+			}
+			continue
+		}
+
+		content, err := readZipFile(f)
+		if err != nil {
+			return fmt.Errorf("failed to read '%s' from odt template: %s", f.Name, err.Error())
+		}
+
+		for _, p := range placeholders {
+			content = strings.ReplaceAll(content, encodeODT(p.key), encodeODT(p.value))
+		}
+
+		header := f.FileHeader
+		fw, err := w.CreateHeader(&header)
+		if err != nil {
+			return err
+		}
+
+		if _, err := fw.Write([]byte(content)); err != nil {
+			return err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return file.WriteAtomic(path.Join(dir, filename), buf.Bytes())
+}
+
+func isODTContentFile(name string) bool {
+	for _, contentFile := range odtContentFiles {
+		if name == contentFile {
+			return true
+		}
+	}
+
+	return false
+}
+
+func readZipFile(f *zip.File) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	return string(data), err
+}
+
+// encodeODT escapes text and converts white characters to the OpenDocument elements
+func encodeODT(text string) string {
+	buf := bytes.NewBuffer(nil)
+	_ = xml.EscapeText(buf, []byte(text))
+
+	return strings.NewReplacer(
+		"&#xD;&#xA;", "<text:line-break/>",
+		"&#xD;", "<text:line-break/>",
+		"&#xA;", "<text:line-break/>",
+		"&#x9;", "<text:tab/>",
+	).Replace(buf.String())
+}
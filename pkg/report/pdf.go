@@ -0,0 +1,312 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"text/template"
+
+	"github.com/pPrecel/PKUP/internal/file"
+	"github.com/pPrecel/PKUP/internal/pdf"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	pdfMargin        = 50.0
+	pdfFontSize      = 10.0
+	pdfTitleSize     = 16.0
+	pdfLineHeight    = 14.0
+	pdfLabelWidth    = 140.0
+	pdfNumberWidth   = 30.0
+	pdfArtifactWidth = 170.0
+	pdfCellPadding   = 4.0
+	pdfSignatureGap  = 60.0
+)
+
+// PDFLayout describes how the pdf report is built
+type PDFLayout struct {
+	// document title
+	Title string `yaml:"title"`
+	// fields printed above the results table
+	Fields []PDFField `yaml:"fields"`
+	// results table configuration
+	Results PDFResults `yaml:"results"`
+	// labels printed under signature lines
+	// e.g.: ["Employee's signature", "Manager's signature"]
+	Signatures []string `yaml:"signatures"`
+}
+
+type PDFField struct {
+	// text printed on the left side
+	Label string `yaml:"label"`
+	// go template filled with report values
+	// e.g.: "{{ .PeriodFrom }} - {{ .PeriodTill }}"
+	Value string `yaml:"value"`
+}
+
+type PDFResults struct {
+	// title printed above the table
+	Title string `yaml:"title"`
+	// columns headers
+	NumberHeader      string `yaml:"numberHeader"`
+	DescriptionHeader string `yaml:"descriptionHeader"`
+	ArtifactHeader    string `yaml:"artifactHeader"`
+}
+
+// DefaultPDFLayout uses labels of the report locale
+var DefaultPDFLayout = PDFLayout{
+	Title: "{{ .Labels.Title }}",
+	Fields: []PDFField{
+		{Label: "{{ .Labels.Employee }}", Value: `{{ index .CustomValues "pkupGenEmployeesName" }}`},
+		{Label: "{{ .Labels.JobTitle }}", Value: `{{ index .CustomValues "pkupGenJobTitle" }}`},
+		{Label: "{{ .Labels.Department }}", Value: `{{ index .CustomValues "pkupGenDepartment" }}`},
+		{Label: "{{ .Labels.Manager }}", Value: `{{ index .CustomValues "pkupGenManagersName" }}`},
+		{Label: "{{ .Labels.Period }}", Value: `{{ .PeriodFrom }} - {{ .PeriodTill }}`},
+		{Label: "{{ .Labels.ApprovalDate }}", Value: `{{ .ApprovalDate }}`},
+		{Label: "{{ .Labels.Contribution }}", Value: `{{ printf .Labels.CommitsSummary .Stats.Commits .Stats.Repos }}, {{ printf .Labels.ChangesSummary .Stats.Additions .Stats.Deletions .Stats.FilesChanged }}`},
+	},
+	Results: PDFResults{
+		Title:             "{{ .Labels.Results }}",
+		NumberHeader:      "{{ .Labels.Number }}",
+		DescriptionHeader: "{{ .Labels.Description }}",
+		ArtifactHeader:    "{{ .Labels.Artifact }}",
+	},
+	Signatures: []string{
+		"{{ .Labels.EmployeeSignature }}",
+		"{{ .Labels.ManagerSignature }}",
+	},
+}
+
+// ReadPDFLayout reads layout from the yaml file
+// returns DefaultPDFLayout when path is empty
+func ReadPDFLayout(path string) (PDFLayout, error) {
+	if path == "" {
+		return DefaultPDFLayout, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return PDFLayout{}, err
+	}
+
+	layout := PDFLayout{}
+	return layout, yaml.Unmarshal(data, &layout)
+}
+
+type pdfRenderer struct {
+	layout PDFLayout
+}
+
+func newPDF(layout PDFLayout) *pdfRenderer {
+	return &pdfRenderer{
+		layout: layout,
+	}
+}
+
+func (pr *pdfRenderer) RenderToFile(dir, filename string, values Values) error {
+	layout, err := executeLayoutTemplates(pr.layout, values)
+	if err != nil {
+		return err
+	}
+
+	w := &pdfPageWriter{doc: pdf.New()}
+	w.newPage()
+
+	if layout.Title != "" {
+		w.doc.Text(pdfMargin, w.y+pdfTitleSize, pdfTitleSize, true, layout.Title)
+		w.y += pdfTitleSize * 2
+	}
+
+	for _, field := range layout.Fields {
+		w.field(field.Label, field.Value)
+	}
+
+	w.y += pdfLineHeight
+	w.results(layout.Results, values)
+	w.signatures(layout.Signatures)
+
+	buf := bytes.NewBuffer(nil)
+	if err := w.doc.Write(buf); err != nil {
+		return err
+	}
+
+	return file.Create(dir, filename, buf.String())
+}
+
+// executeLayoutTemplates returns copy of the layout with all texts filled with values
+func executeLayoutTemplates(layout PDFLayout, values Values) (PDFLayout, error) {
+	var err error
+	out := PDFLayout{
+		Fields:     make([]PDFField, len(layout.Fields)),
+		Signatures: make([]string, len(layout.Signatures)),
+	}
+
+	texts := map[*string]string{
+		&out.Title:                     layout.Title,
+		&out.Results.Title:             layout.Results.Title,
+		&out.Results.NumberHeader:      layout.Results.NumberHeader,
+		&out.Results.DescriptionHeader: layout.Results.DescriptionHeader,
+		&out.Results.ArtifactHeader:    layout.Results.ArtifactHeader,
+	}
+	for i := range layout.Fields {
+		texts[&out.Fields[i].Label] = layout.Fields[i].Label
+		texts[&out.Fields[i].Value] = layout.Fields[i].Value
+	}
+	for i := range layout.Signatures {
+		texts[&out.Signatures[i]] = layout.Signatures[i]
+	}
+
+	for dest, text := range texts {
+		*dest, err = executeFieldTemplate(text, values)
+		if err != nil {
+			return PDFLayout{}, fmt.Errorf("failed to render pdf layout text '%s': %s", text, err.Error())
+		}
+	}
+
+	return out, nil
+}
+
+func executeFieldTemplate(text string, values Values) (string, error) {
+	tmpl, err := template.New("field").Funcs(textTemplateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = tmpl.Execute(buf, values)
+	return buf.String(), err
+}
+
+type pdfPageWriter struct {
+	doc *pdf.Document
+	// y position of the cursor measured from the top of the page
+	y float64
+}
+
+func (w *pdfPageWriter) newPage() {
+	w.doc.AddPage()
+	w.y = pdfMargin
+}
+
+// ensureSpace opens new page if the given height does not fit in the current one
+func (w *pdfPageWriter) ensureSpace(height float64) bool {
+	if w.y+height > pdf.PageHeight-pdfMargin {
+		w.newPage()
+		return true
+	}
+
+	return false
+}
+
+func (w *pdfPageWriter) field(label, value string) {
+	valueWidth := pdf.PageWidth - 2*pdfMargin - pdfLabelWidth
+	lines := pdf.WrapText(value, valueWidth, pdfFontSize, false)
+
+	w.ensureSpace(float64(len(lines)) * pdfLineHeight)
+	w.doc.Text(pdfMargin, w.y+pdfFontSize, pdfFontSize, true, label)
+	for _, line := range lines {
+		w.doc.Text(pdfMargin+pdfLabelWidth, w.y+pdfFontSize, pdfFontSize, false, line)
+		w.y += pdfLineHeight
+	}
+}
+
+func (w *pdfPageWriter) results(results PDFResults, values Values) {
+	if results.Title != "" {
+		w.ensureSpace(pdfLineHeight * 3)
+		w.doc.Text(pdfMargin, w.y+pdfFontSize+2, pdfFontSize+2, true, results.Title)
+		w.y += pdfLineHeight * 1.5
+	}
+
+	columns := []float64{
+		pdfNumberWidth,
+		pdf.PageWidth - 2*pdfMargin - pdfNumberWidth - pdfArtifactWidth,
+		pdfArtifactWidth,
+	}
+
+	w.row(columns, []string{results.NumberHeader, results.DescriptionHeader, results.ArtifactHeader}, true)
+
+	number := 1
+	for _, group := range values.Groups {
+		if values.GroupBy != "" {
+			if w.ensureSpace(pdfLineHeight*2 + pdfCellPadding*2) {
+				w.row(columns, []string{results.NumberHeader, results.DescriptionHeader, results.ArtifactHeader}, true)
+			}
+
+			w.row([]float64{sum(columns)}, []string{group.Title}, true)
+		}
+
+		for _, commit := range group.Commits {
+			cells := []string{
+				fmt.Sprintf("%d.", number),
+				fmt.Sprintf("%s (%s/%s)", commit.Task, commit.Org, commit.Repo),
+				commit.DiffFile,
+			}
+
+			if w.ensureSpace(w.rowHeight(columns, cells, false)) {
+				// repeat table header on the new page
+				w.row(columns, []string{results.NumberHeader, results.DescriptionHeader, results.ArtifactHeader}, true)
+			}
+
+			w.row(columns, cells, false)
+			number++
+		}
+	}
+}
+
+func (w *pdfPageWriter) rowHeight(columns []float64, cells []string, bold bool) float64 {
+	maxLines := 1
+	for i := range cells {
+		lines := pdf.WrapText(cells[i], columns[i]-2*pdfCellPadding, pdfFontSize, bold)
+		if len(lines) > maxLines {
+			maxLines = len(lines)
+		}
+	}
+
+	return float64(maxLines)*pdfLineHeight + pdfCellPadding
+}
+
+// row draws table row with borders
+func (w *pdfPageWriter) row(columns []float64, cells []string, bold bool) {
+	height := w.rowHeight(columns, cells, bold)
+	w.ensureSpace(height)
+
+	x := pdfMargin
+	for i := range cells {
+		lines := pdf.WrapText(cells[i], columns[i]-2*pdfCellPadding, pdfFontSize, bold)
+		for j, line := range lines {
+			w.doc.Text(x+pdfCellPadding, w.y+pdfFontSize+float64(j)*pdfLineHeight+pdfCellPadding/2, pdfFontSize, bold, line)
+		}
+
+		w.doc.Line(x, w.y, x, w.y+height)
+		x += columns[i]
+	}
+
+	w.doc.Line(x, w.y, x, w.y+height)
+	w.doc.Line(pdfMargin, w.y, x, w.y)
+	w.doc.Line(pdfMargin, w.y+height, x, w.y+height)
+	w.y += height
+}
+
+func (w *pdfPageWriter) signatures(labels []string) {
+	width := (pdf.PageWidth - 2*pdfMargin) / 2
+	for i := 0; i < len(labels); i += 2 {
+		w.ensureSpace(pdfSignatureGap + pdfLineHeight)
+		w.y += pdfSignatureGap
+
+		for j := i; j < i+2 && j < len(labels); j++ {
+			x := pdfMargin + float64(j-i)*width
+			w.doc.Line(x, w.y, x+width-pdfMargin, w.y)
+			w.doc.Text(x, w.y+pdfFontSize+2, pdfFontSize-1, false, labels[j])
+		}
+		w.y += pdfLineHeight
+	}
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+
+	return total
+}
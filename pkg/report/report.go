@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	go_github "github.com/google/go-github/v53/github"
 	"github.com/pPrecel/PKUP/internal/file"
 	"github.com/pPrecel/PKUP/pkg/github"
 )
@@ -14,44 +15,362 @@ const (
 	PeriodFormat = "02.01.2006"
 )
 
+const (
+	FormatTxt      = "txt"
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+	FormatPDF      = "pdf"
+	FormatJSON     = "json"
+	FormatYAML     = "yaml"
+)
+
+// list of all formats supported by the Render func
+var Formats = []string{
+	FormatTxt,
+	FormatMarkdown,
+	FormatHTML,
+	FormatPDF,
+	FormatJSON,
+	FormatYAML,
+}
+
 type Result struct {
-	Org  string
-	Repo string
+	Org  string `json:"org"`
+	Repo string `json:"repo"`
 	// URL        string
-	CommitList github.CommitList
+	CommitList github.CommitList `json:"commitList"`
+	// pull requests associated with commits by the commit SHA
+	PullRequests map[string][]github.PullRequest `json:"pullRequests,omitempty"`
+	// SHAs of commits created by bots that should be marked in the report
+	BotCommits map[string]bool `json:"botCommits,omitempty"`
 }
 
+// prefix of messages of commits created by bots
+const botMessagePrefix = "[bot] "
+
 type Options struct {
 	OutputDir    string
 	TemplatePath string
+	// additional report formats generated next to the template report ( e.g. "md", "html" )
+	Formats []string
+	// path to the yaml file with the pdf report layout ( default: DefaultPDFLayout )
+	PDFLayoutPath string
+	// split results into groups ( one of GroupByOptions )
+	GroupBy string
+	// languages of the report ( one of Locales ), every locale is rendered to separate files when more than one is set
+	Locales      []string
 	PeriodFrom   time.Time
 	PeriodTill   time.Time
 	Results      []Result
 	CustomValues map[string]string
+	// checks run on rendered files
+	Validation ValidationOptions
+}
+
+type Output struct {
+	// paths of all rendered files
+	Files []string
+	// validation issues found in the warn mode
+	Warnings []string
+}
+
+type renderer interface {
+	RenderToFile(dir, filename string, values Values) error
 }
 
-func Render(opts Options) error {
+func Render(opts Options) (*Output, error) {
+	if err := ValidateGroupBy(opts.GroupBy); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateValidationMode(opts.Validation.Mode); err != nil {
+		return nil, err
+	}
+
+	// the template is parsed once and used for all locales
+	var templateRenderer renderer
+	if opts.TemplatePath != "" {
+		var err error
+		templateRenderer, err = newForTemplate(opts.TemplatePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := validateTemplateOutput(opts); err != nil {
+		return nil, err
+	}
+
+	issues := []string{}
+	if opts.Validation.Mode != ValidationModeOff {
+		// validate before writing any file so the failed report is not left in the output dir
+		var err error
+		issues, err = validateTemplate(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(issues) > 0 && opts.Validation.Mode == ValidationModeFail {
+			return nil, fmt.Errorf("report validation failed: %s", strings.Join(issues, "; "))
+		}
+	}
+
+	locales := reportLocales(opts)
+	output := &Output{
+		Warnings: issues,
+	}
+	for _, locale := range locales {
+		if locale != "" {
+			if err := ValidateLocale(locale); err != nil {
+				return nil, err
+			}
+		}
+
+		files, err := renderForLocale(opts, templateRenderer, getLocale(locale), locale, localeFilenameSuffix(locales, locale))
+		if err != nil {
+			return nil, err
+		}
+
+		output.Files = append(output.Files, files...)
+	}
+
+	return output, nil
+}
+
+// renderForLocale renders all reports and returns paths to them
+// templateRenderer is nil when the template is not set
+func renderForLocale(opts Options, templateRenderer renderer, locale Locale, localeName, filenameSuffix string) ([]string, error) {
+	repos := buildReportRepos(opts)
+	approvalDate := opts.PeriodTill.Add(time.Hour * 24)
 	values := Values{
-		PeriodFrom:   opts.PeriodFrom.Format(PeriodFormat),
-		PeriodTill:   opts.PeriodTill.Format(PeriodFormat),
-		ApprovalDate: opts.PeriodTill.Add(time.Hour * 24).Format(PeriodFormat),
-		Result:       buildreportResult(opts),
-		CustomValues: opts.CustomValues,
+		Locale:           localeName,
+		Labels:           locale.Labels,
+		PeriodFrom:       locale.FormatDate(opts.PeriodFrom),
+		PeriodTill:       locale.FormatDate(opts.PeriodTill),
+		ApprovalDate:     locale.FormatDate(approvalDate),
+		PeriodFromLong:   locale.FormatLongDate(opts.PeriodFrom),
+		PeriodTillLong:   locale.FormatLongDate(opts.PeriodTill),
+		ApprovalDateLong: locale.FormatLongDate(approvalDate),
+		PeriodMonth:      locale.FormatMonth(opts.PeriodTill),
+		Result:           buildreportResult(opts),
+		Repos:            repos,
+		GroupBy:          opts.GroupBy,
+		Groups:           buildReportGroups(repos, opts.GroupBy, locale.Labels.Other),
+		Stats:            sumCommitStats(allCommits(repos)),
+		CustomValues:     opts.CustomValues,
+	}
+
+	files := []string{}
+	if templateRenderer != nil {
+		filename := withFilenameSuffix(templateOutputFilename(opts.TemplatePath), filenameSuffix)
+		err := templateRenderer.RenderToFile(opts.OutputDir, filename, values)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, filepath.Join(opts.OutputDir, filename))
+	}
+
+	for _, format := range reportFormats(opts) {
+		r, err := newForFormat(format, opts, localeName)
+		if err != nil {
+			return nil, err
+		}
+
+		filename := withFilenameSuffix(fmt.Sprintf("report.%s", format), filenameSuffix)
+		err = r.RenderToFile(opts.OutputDir, filename, values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render '%s' report: %s", format, err.Error())
+		}
+
+		files = append(files, filepath.Join(opts.OutputDir, filename))
+	}
+
+	return files, nil
+}
+
+// OutputFiles returns paths of files the Render func would write without rendering them
+// the template is parsed to find problems before rendering
+func OutputFiles(opts Options) ([]string, error) {
+	if err := ValidateGroupBy(opts.GroupBy); err != nil {
+		return nil, err
 	}
 
 	if opts.TemplatePath != "" {
-		return newFromTemplate(opts.TemplatePath).RenderToFile(
-			opts.OutputDir,
-			filepath.Base(opts.TemplatePath),
-			values,
-		)
+		if _, err := newForTemplate(opts.TemplatePath); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := validateTemplateOutput(opts); err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	locales := reportLocales(opts)
+	for _, locale := range locales {
+		if locale != "" {
+			if err := ValidateLocale(locale); err != nil {
+				return nil, err
+			}
+		}
+
+		suffix := localeFilenameSuffix(locales, locale)
+		if opts.TemplatePath != "" {
+			filename := withFilenameSuffix(templateOutputFilename(opts.TemplatePath), suffix)
+			files = append(files, filepath.Join(opts.OutputDir, filename))
+		}
+
+		for _, format := range reportFormats(opts) {
+			if err := ValidateFormat(format); err != nil {
+				return nil, err
+			}
+
+			filename := withFilenameSuffix(fmt.Sprintf("report.%s", format), suffix)
+			files = append(files, filepath.Join(opts.OutputDir, filename))
+		}
+	}
+
+	return files, nil
+}
+
+func reportLocales(opts Options) []string {
+	if len(opts.Locales) == 0 {
+		// render once using the default locale
+		return []string{""}
+	}
+
+	return opts.Locales
+}
+
+// localeFilenameSuffix returns suffix of files rendered for the locale ( e.g. "_pl" )
+func localeFilenameSuffix(locales []string, locale string) string {
+	if len(locales) > 1 {
+		// every language variant is saved in separate file
+		return "_" + locale
+	}
+
+	return ""
+}
+
+func reportFormats(opts Options) []string {
+	if opts.TemplatePath == "" && len(opts.Formats) == 0 {
+		// render the default report when nothing else is specified
+		return []string{FormatTxt}
+	}
+
+	return opts.Formats
+}
+
+// withFilenameSuffix adds suffix before the file extension ( e.g. report.txt -> report_pl.txt )
+func withFilenameSuffix(filename, suffix string) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + suffix + ext
+}
+
+func ValidateFormat(format string) error {
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
 	}
 
-	return newDefault().RenderToFile(
-		opts.OutputDir,
-		"report.txt",
-		values,
-	)
+	return fmt.Errorf("unsupported report format '%s' (supported: %s)", format, strings.Join(Formats, ", "))
+}
+
+// newForTemplate returns renderer based on the template file extension
+func newForTemplate(path string) (renderer, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".docx":
+		return newFromTemplate(path), nil
+	case ".odt":
+		return newODTFromTemplate(path), nil
+	case ".tmpl", ".txt", ".md":
+		return newFromTextTemplate(path)
+	default:
+		return nil, fmt.Errorf("unsupported template '%s' (supported extensions: .docx, .odt, .tmpl, .txt, .md)", filepath.Base(path))
+	}
+}
+
+// templateOutputFilename returns name of the rendered template
+// the .tmpl extension is trimmed ( e.g. report.md.tmpl -> report.md, report.tmpl -> report.txt )
+func templateOutputFilename(path string) string {
+	filename := filepath.Base(path)
+	if !strings.EqualFold(filepath.Ext(filename), ".tmpl") {
+		return filename
+	}
+
+	filename = strings.TrimSuffix(filename, filepath.Ext(filename))
+	if filepath.Ext(filename) == "" {
+		filename += ".txt"
+	}
+
+	return filename
+}
+
+// validateTemplateOutput checks if the rendered template doesn't overwrite other report files or the template itself
+func validateTemplateOutput(opts Options) error {
+	if opts.TemplatePath == "" {
+		return nil
+	}
+
+	filename := templateOutputFilename(opts.TemplatePath)
+	for _, format := range opts.Formats {
+		if strings.EqualFold(filename, fmt.Sprintf("report.%s", format)) {
+			return fmt.Errorf("rendered template '%s' would be overwritten by the '%s' report format (rename the template)", filename, format)
+		}
+	}
+
+	if strings.EqualFold(filename, ManifestFilename) {
+		return fmt.Errorf("rendered template '%s' would be overwritten by the manifest (rename the template)", filename)
+	}
+
+	templatePath, err := filepath.Abs(opts.TemplatePath)
+	if err != nil {
+		return err
+	}
+
+	locales := reportLocales(opts)
+	for _, locale := range locales {
+		outputPath, err := filepath.Abs(filepath.Join(opts.OutputDir, withFilenameSuffix(filename, localeFilenameSuffix(locales, locale))))
+		if err != nil {
+			return err
+		}
+
+		if outputPath == templatePath {
+			return fmt.Errorf("template '%s' would be overwritten by the rendered report (move it out of the output dir or rename it)", opts.TemplatePath)
+		}
+	}
+
+	return nil
+}
+
+func newForFormat(format string, opts Options, localeName string) (renderer, error) {
+	switch format {
+	case FormatTxt:
+		if localeName != "" {
+			return newLocalizedDefault(), nil
+		}
+
+		return newDefault(), nil
+	case FormatMarkdown:
+		return newMarkdown(), nil
+	case FormatHTML:
+		return newHTML(), nil
+	case FormatPDF:
+		layout, err := ReadPDFLayout(opts.PDFLayoutPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pdf layout: %s", err.Error())
+		}
+
+		return newPDF(layout), nil
+	case FormatJSON:
+		return newJSONExport(), nil
+	case FormatYAML:
+		return newYAMLExport(), nil
+	default:
+		return nil, ValidateFormat(format)
+	}
 }
 
 func buildreportResult(opts Options) []string {
@@ -61,19 +380,155 @@ func buildreportResult(opts Options) []string {
 			org := result.Org
 			repo := result.Repo
 			commit := result.CommitList.Commits[i]
-			results = append(
-				results,
-				fmt.Sprintf(
+			message := taskTitle(strings.Split(commit.Commit.GetMessage(), "\n")[0], result.PullRequests[commit.GetSHA()])
+			if result.BotCommits[commit.GetSHA()] {
+				message = botMessagePrefix + message
+			}
+			if diffFile := diffFilename(org, repo, commit); diffFile != "" {
+				message = fmt.Sprintf(
 					"%s (%s)",
-					strings.Split(commit.Commit.GetMessage(), "\n")[0],
-					file.BuildDiffFilename(commit.GetSHA(), org, repo),
+					message,
+					diffFile,
 					// "<a href=\"%s/%s/%s/commit/%s\">%s</a> (%s)",
 					// result.URL, org, repo, commit.GetSHA(), // commit link
 					// strings.Split(commit.Commit.GetMessage(), "\n")[0], // commit message
 					// file.BuildDiffFilename(commit.GetSHA(), org, repo), // file name
-				),
-			)
+				)
+			}
+			results = append(results, message)
 		}
 	}
 	return results
 }
+
+// diffFilename returns name of the .diff artifact saved next to the report
+// returns empty string for commits with empty diffs ( artifacts are not saved for them )
+func diffFilename(org, repo string, commit *go_github.RepositoryCommit) string {
+	if len(commit.Files) == 0 {
+		return ""
+	}
+
+	return file.BuildDiffFilename(commit.GetSHA(), org, repo)
+}
+
+func buildReportRepos(opts Options) []RepoValues {
+	repos := []RepoValues{}
+	for _, result := range opts.Results {
+		if len(result.CommitList.Commits) == 0 {
+			continue
+		}
+
+		repo := RepoValues{
+			Org:     result.Org,
+			Repo:    result.Repo,
+			Commits: []CommitValues{},
+		}
+		for _, commit := range result.CommitList.Commits {
+			message, body, _ := strings.Cut(commit.GetCommit().GetMessage(), "\n")
+			task := taskTitle(message, result.PullRequests[commit.GetSHA()])
+			bot := result.BotCommits[commit.GetSHA()]
+			if bot {
+				message = botMessagePrefix + message
+				task = botMessagePrefix + task
+			}
+			repo.Commits = append(repo.Commits, CommitValues{
+				Org:      result.Org,
+				Repo:     result.Repo,
+				SHA:      commit.GetSHA(),
+				Message:  message,
+				Body:     strings.TrimSpace(body),
+				Task:     task,
+				URL:      commit.GetHTMLURL(),
+				Bot:      bot,
+				Date:     commit.GetCommit().GetAuthor().GetDate().Time,
+				DiffFile: diffFilename(result.Org, result.Repo, commit),
+				Stats: Stats{
+					Commits:      1,
+					Repos:        1,
+					Additions:    commit.GetStats().GetAdditions(),
+					Deletions:    commit.GetStats().GetDeletions(),
+					FilesChanged: len(commit.Files),
+				},
+				PullRequests: buildPullRequestValues(result.PullRequests[commit.GetSHA()]),
+			})
+		}
+
+		repo.Stats = sumCommitStats(repo.Commits)
+		repos = append(repos, repo)
+	}
+
+	return repos
+}
+
+// taskTitle returns the title of the first pull request with titles of issues closed by it
+// the message is returned for commits without pull requests
+func taskTitle(message string, pullRequests []github.PullRequest) string {
+	if len(pullRequests) == 0 {
+		return message
+	}
+
+	pr := pullRequests[0]
+	title := fmt.Sprintf("%s (#%d)", pr.Title, pr.Number)
+	issues := []string{}
+	for _, issue := range pr.Issues {
+		if issue.Title != "" {
+			issues = append(issues, issue.Title)
+		}
+	}
+	if len(issues) > 0 {
+		title += ": " + strings.Join(issues, "; ")
+	}
+
+	return title
+}
+
+func buildPullRequestValues(pullRequests []github.PullRequest) []PullRequestValues {
+	values := []PullRequestValues{}
+	for _, pr := range pullRequests {
+		issues := []IssueValues{}
+		for _, issue := range pr.Issues {
+			issues = append(issues, IssueValues{
+				Org:    issue.Org,
+				Repo:   issue.Repo,
+				Number: issue.Number,
+				Title:  issue.Title,
+				URL:    issue.URL,
+			})
+		}
+
+		values = append(values, PullRequestValues{
+			Number: pr.Number,
+			Title:  pr.Title,
+			Body:   pr.Body,
+			URL:    pr.URL,
+			Issues: issues,
+		})
+	}
+
+	return values
+}
+
+func allCommits(repos []RepoValues) []CommitValues {
+	commits := []CommitValues{}
+	for _, repo := range repos {
+		commits = append(commits, repo.Commits...)
+	}
+
+	return commits
+}
+
+// sumCommitStats sums stats of all commits and counts unique repositories
+func sumCommitStats(commits []CommitValues) Stats {
+	stats := Stats{}
+	repos := map[string]struct{}{}
+	for _, commit := range commits {
+		stats.Commits += commit.Stats.Commits
+		stats.Additions += commit.Stats.Additions
+		stats.Deletions += commit.Stats.Deletions
+		stats.FilesChanged += commit.Stats.FilesChanged
+		repos[fmt.Sprintf("%s/%s", commit.Org, commit.Repo)] = struct{}{}
+	}
+
+	stats.Repos = len(repos)
+	return stats
+}
@@ -0,0 +1,603 @@
+package report
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	go_github "github.com/google/go-github/v53/github"
+	"github.com/pPrecel/PKUP/pkg/github"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	"k8s.io/utils/ptr"
+)
+
+var (
+	testResults = []Result{
+		{
+			Org:  "test-org",
+			Repo: "test-repo",
+			CommitList: github.CommitList{
+				Commits: []*go_github.RepositoryCommit{
+					{
+						SHA:     ptr.To("sha1"),
+						HTMLURL: ptr.To("https://github.com/test-org/test-repo/commit/sha1"),
+						Stats: &go_github.CommitStats{
+							Additions: ptr.To(10),
+							Deletions: ptr.To(2),
+						},
+						Files: []*go_github.CommitFile{{}, {}},
+						Commit: &go_github.Commit{
+							Message: ptr.To("test PR 1 (#123)\n\ndescription"),
+							Author: &go_github.CommitAuthor{
+								Date: &go_github.Timestamp{Time: time.Date(2023, 10, 2, 12, 0, 0, 0, time.UTC)},
+							},
+						},
+					},
+					{
+						SHA:     ptr.To("sha2"),
+						HTMLURL: ptr.To("https://github.com/test-org/test-repo/commit/sha2"),
+						Commit: &go_github.Commit{
+							Message: ptr.To("test <PR> 2 (#124)"),
+						},
+					},
+				},
+			},
+		},
+		{
+			Org:  "test-org",
+			Repo: "empty-repo",
+		},
+	}
+)
+
+func TestRender(t *testing.T) {
+	t.Run("render default report", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		_, err := Render(Options{
+			OutputDir:  tmpDir,
+			PeriodFrom: time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC),
+			PeriodTill: time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC),
+			Results:    testResults,
+		})
+		require.NoError(t, err)
+
+		body, err := os.ReadFile(path.Join(tmpDir, "report.txt"))
+		require.NoError(t, err)
+		require.Contains(t, string(body), "19.09.2023 - 18.10.2023")
+		require.Contains(t, string(body), "- test PR 1 (#123) (test-org_test-repo_sha1.diff)")
+	})
+
+	t.Run("flag bot commits", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		_, err := Render(Options{
+			OutputDir:  tmpDir,
+			Formats:    []string{FormatTxt, FormatJSON},
+			PeriodFrom: time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC),
+			PeriodTill: time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC),
+			Results: []Result{
+				{
+					Org:        testResults[0].Org,
+					Repo:       testResults[0].Repo,
+					CommitList: testResults[0].CommitList,
+					BotCommits: map[string]bool{"sha2": true},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		body, err := os.ReadFile(path.Join(tmpDir, "report.txt"))
+		require.NoError(t, err)
+		require.Contains(t, string(body), "- test PR 1 (#123) (test-org_test-repo_sha1.diff)")
+		require.Contains(t, string(body), "- [bot] test <PR> 2 (#124)")
+
+		body, err = os.ReadFile(path.Join(tmpDir, "report.json"))
+		require.NoError(t, err)
+		require.Contains(t, string(body), `"bot": true`)
+	})
+
+	t.Run("render markdown and html reports", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		_, err := Render(Options{
+			OutputDir:  tmpDir,
+			Formats:    []string{FormatMarkdown, FormatHTML},
+			PeriodFrom: time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC),
+			PeriodTill: time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC),
+			Results:    testResults,
+		})
+		require.NoError(t, err)
+		require.NoFileExists(t, path.Join(tmpDir, "report.txt"))
+
+		body, err := os.ReadFile(path.Join(tmpDir, "report.md"))
+		require.NoError(t, err)
+		require.Contains(t, string(body), "**Commits:** 2 in 1 repositories")
+		require.Contains(t, string(body), "**Changes:** +10/-2 lines in 2 files")
+		require.Contains(t, string(body), "## test-org/test-repo")
+		require.Contains(t, string(body), "- [test PR 1 (#123)](https://github.com/test-org/test-repo/commit/sha1) (`test-org_test-repo_sha1.diff`)")
+		// sha2 has no artifact because of the empty diff
+		require.Contains(t, string(body), "- [test \\<PR\\> 2 (#124)](https://github.com/test-org/test-repo/commit/sha2)\n")
+		require.NotContains(t, string(body), "empty-repo")
+
+		body, err = os.ReadFile(path.Join(tmpDir, "report.html"))
+		require.NoError(t, err)
+		require.Contains(t, string(body), `<a href="https://github.com/test-org/test-repo/commit/sha2">test &lt;PR&gt; 2 (#124)</a></li>`)
+		require.Contains(t, string(body), "<h2>test-org/test-repo <small>2 commits, +10/-2 lines in 2 files</small></h2>")
+	})
+
+	t.Run("render pdf report", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		_, err := Render(Options{
+			OutputDir:  tmpDir,
+			Formats:    []string{FormatPDF},
+			PeriodFrom: time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC),
+			PeriodTill: time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC),
+			Results:    testResults,
+			CustomValues: map[string]string{
+				"pkupGenEmployeesName": "John Wick",
+			},
+		})
+		require.NoError(t, err)
+
+		body, err := os.ReadFile(path.Join(tmpDir, "report.pdf"))
+		require.NoError(t, err)
+		require.Contains(t, string(body), "%PDF-1.4")
+		require.Contains(t, string(body), "(John Wick) Tj")
+		require.Contains(t, string(body), "(19.09.2023 - 18.10.2023) Tj")
+		require.Contains(t, string(body), "(test-org_test-repo_sha1.diff) Tj")
+		require.Contains(t, string(body), "(Manager's signature) Tj")
+	})
+
+	t.Run("render json and yaml exports", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		_, err := Render(Options{
+			OutputDir:  tmpDir,
+			Formats:    []string{FormatJSON, FormatYAML},
+			PeriodFrom: time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC),
+			PeriodTill: time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC),
+			Results:    testResults,
+			CustomValues: map[string]string{
+				"pkupGenEmployeesName": "John Wick",
+			},
+		})
+		require.NoError(t, err)
+
+		body, err := os.ReadFile(path.Join(tmpDir, "report.json"))
+		require.NoError(t, err)
+
+		jsonExport := Export{}
+		require.NoError(t, json.Unmarshal(body, &jsonExport))
+		require.Equal(t, ExportSchemaVersion, jsonExport.SchemaVersion)
+		require.Equal(t, "19.09.2023", jsonExport.PeriodFrom)
+		require.Equal(t, "John Wick", jsonExport.CustomValues["pkupGenEmployeesName"])
+		require.Len(t, jsonExport.Commits, 2)
+		require.Equal(t, CommitValues{
+			Org:          "test-org",
+			Repo:         "test-repo",
+			SHA:          "sha1",
+			Message:      "test PR 1 (#123)",
+			Body:         "description",
+			Task:         "test PR 1 (#123)",
+			URL:          "https://github.com/test-org/test-repo/commit/sha1",
+			Date:         time.Date(2023, 10, 2, 12, 0, 0, 0, time.UTC),
+			DiffFile:     "test-org_test-repo_sha1.diff",
+			Stats:        Stats{Commits: 1, Repos: 1, Additions: 10, Deletions: 2, FilesChanged: 2},
+			PullRequests: []PullRequestValues{},
+		}, jsonExport.Commits[0])
+		require.Empty(t, jsonExport.Commits[1].DiffFile)
+		require.Contains(t, string(body), `"schemaVersion": "1"`)
+
+		body, err = os.ReadFile(path.Join(tmpDir, "report.yaml"))
+		require.NoError(t, err)
+
+		yamlExport := Export{}
+		require.NoError(t, yaml.Unmarshal(body, &yamlExport))
+		require.Equal(t, jsonExport, yamlExport)
+	})
+
+	t.Run("render odt template", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		templatePath := fixODTTemplate(t, tmpDir, map[string]string{
+			"content.xml": "<office:text><text:p>pkupGenEmployeesName</text:p><text:p>pkupGenResults</text:p></office:text>",
+			"styles.xml":  "<style:header><text:p>pkupGenPeriodFrom - pkupGenPeriodTill (pkupGenCommitCount, +pkupGenLinesAdded)</text:p></style:header>",
+		})
+		outputDir := path.Join(tmpDir, "out")
+		require.NoError(t, os.Mkdir(outputDir, os.ModePerm))
+
+		_, err := Render(Options{
+			OutputDir:    outputDir,
+			TemplatePath: templatePath,
+			PeriodFrom:   time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC),
+			PeriodTill:   time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC),
+			Results:      testResults,
+			CustomValues: map[string]string{
+				"pkupGenEmployeesName": "John & Wick",
+			},
+		})
+		require.NoError(t, err)
+		require.NoFileExists(t, path.Join(outputDir, "report.txt"))
+
+		r, err := zip.OpenReader(path.Join(outputDir, "template.odt"))
+		require.NoError(t, err)
+		defer r.Close()
+
+		require.Equal(t, "mimetype", r.File[0].Name)
+		require.Equal(t, zip.Store, r.File[0].Method)
+
+		content, err := readZipFile(r.File[1])
+		require.NoError(t, err)
+		require.Equal(t, "<office:text><text:p>John &amp; Wick</text:p><text:p>"+
+			"- test PR 1 (#123) (test-org_test-repo_sha1.diff)<text:line-break/>"+
+			"- test &lt;PR&gt; 2 (#124)<text:line-break/>"+
+			"</text:p></office:text>", content)
+
+		styles, err := readZipFile(r.File[2])
+		require.NoError(t, err)
+		require.Equal(t, "<style:header><text:p>19.09.2023 - 18.10.2023 (2, +10)</text:p></style:header>", styles)
+	})
+
+	t.Run("render docx template", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		templatePath := fixDocxTemplate(t, tmpDir, "<w:body><w:t>pkupGenEmployeesName pkupGenPeriodFrom</w:t></w:body>")
+		outputDir := path.Join(tmpDir, "out")
+		require.NoError(t, os.Mkdir(outputDir, os.ModePerm))
+
+		output, err := Render(Options{
+			OutputDir:    outputDir,
+			TemplatePath: templatePath,
+			PeriodFrom:   time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC),
+			PeriodTill:   time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC),
+			Results:      testResults,
+			CustomValues: map[string]string{
+				"pkupGenEmployeesName": "John Wick",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{path.Join(outputDir, "template.docx")}, output.Files)
+
+		r, err := zip.OpenReader(path.Join(outputDir, "template.docx"))
+		require.NoError(t, err)
+		defer r.Close()
+
+		documents := map[string]string{}
+		for _, f := range r.File {
+			content, err := readZipFile(f)
+			require.NoError(t, err)
+			documents[f.Name] = content
+		}
+		require.Equal(t, "<w:body><w:t>John Wick 19.09.2023</w:t></w:body>", documents["word/document.xml"])
+
+		// only the rendered report is left in the output dir
+		entries, err := os.ReadDir(outputDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("render enriched results as tasks", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		templatePath := fixODTTemplate(t, tmpDir, map[string]string{
+			"content.xml": "<office:text><text:p>pkupGenResults</text:p></office:text>",
+		})
+		outputDir := path.Join(tmpDir, "out")
+		require.NoError(t, os.Mkdir(outputDir, os.ModePerm))
+
+		opts := Options{
+			OutputDir:    outputDir,
+			TemplatePath: templatePath,
+			Formats:      Formats,
+			Results: []Result{
+				{
+					Org:        testResults[0].Org,
+					Repo:       testResults[0].Repo,
+					CommitList: testResults[0].CommitList,
+					PullRequests: map[string][]github.PullRequest{
+						"sha1": {
+							{Number: 123, Title: "Add feature", Issues: []github.Issue{{Number: 5, Title: "Feature request"}}},
+						},
+						"sha2": {
+							{Number: 124, Title: "Fix bug"},
+						},
+					},
+				},
+			},
+		}
+		_, err := Render(opts)
+		require.NoError(t, err)
+
+		// every format lists the same tasks
+		for filename, task := range map[string]string{
+			"report.txt":  "- Add feature (#123): Feature request (test-org_test-repo_sha1.diff)",
+			"report.md":   "[Add feature (#123): Feature request](https://github.com/test-org/test-repo/commit/sha1)",
+			"report.html": `<a href="https://github.com/test-org/test-repo/commit/sha1">Add feature (#123): Feature request</a>`,
+			"report.pdf":  `(Add feature \(#123\): Feature request \(test-org/test-repo\)) Tj`,
+			"report.json": `"task": "Add feature (#123): Feature request"`,
+			"report.yaml": "task: 'Add feature (#123): Feature request'",
+		} {
+			body, err := os.ReadFile(path.Join(outputDir, filename))
+			require.NoError(t, err)
+			require.Contains(t, string(body), task, filename)
+		}
+
+		// grouped default report
+		groupedDir := path.Join(tmpDir, "grouped")
+		require.NoError(t, os.Mkdir(groupedDir, os.ModePerm))
+		opts.OutputDir = groupedDir
+		opts.TemplatePath = ""
+		opts.Formats = nil
+		opts.GroupBy = GroupByRepo
+		_, err = Render(opts)
+		require.NoError(t, err)
+
+		body, err := os.ReadFile(path.Join(groupedDir, "report.txt"))
+		require.NoError(t, err)
+		require.Contains(t, string(body), "  - Add feature (#123): Feature request (test-org_test-repo_sha1.diff)\n  - Fix bug (#124)\n")
+
+		r, err := zip.OpenReader(path.Join(outputDir, "template.odt"))
+		require.NoError(t, err)
+		defer r.Close()
+
+		content, err := readZipFile(r.File[1])
+		require.NoError(t, err)
+		require.Equal(t, "<office:text><text:p>"+
+			"- Add feature (#123): Feature request (test-org_test-repo_sha1.diff)<text:line-break/>"+
+			"- Fix bug (#124)<text:line-break/>"+
+			"</text:p></office:text>", content)
+	})
+
+	t.Run("render text template", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		templatePath := path.Join(tmpDir, "summary.md.tmpl")
+		require.NoError(t, os.WriteFile(templatePath, []byte(
+			"# {{ upper (index .CustomValues \"name\") }} {{ default \"-\" .ApprovalDate }}\n"+
+				"{{ range $i, $repo := .Repos }}{{ range .Commits }}{{ add $i 1 }}. {{ escapeMarkdown .Message }}\n"+
+				"{{ range .PullRequests }}   #{{ .Number }} {{ .Title }}{{ range .Issues }} ({{ .Title }}){{ end }}\n{{ end }}{{ end }}{{ end }}",
+		), os.ModePerm))
+		outputDir := path.Join(tmpDir, "out")
+		require.NoError(t, os.Mkdir(outputDir, os.ModePerm))
+
+		_, err := Render(Options{
+			OutputDir:    outputDir,
+			TemplatePath: templatePath,
+			PeriodFrom:   time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC),
+			PeriodTill:   time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC),
+			Results: []Result{
+				{
+					Org:        testResults[0].Org,
+					Repo:       testResults[0].Repo,
+					CommitList: testResults[0].CommitList,
+					PullRequests: map[string][]github.PullRequest{
+						"sha1": {
+							{Number: 123, Title: "Add feature", Issues: []github.Issue{{Number: 5, Title: "Feature request"}}},
+						},
+					},
+				},
+			},
+			CustomValues: map[string]string{
+				"name": "john",
+			},
+		})
+		require.NoError(t, err)
+
+		body, err := os.ReadFile(path.Join(outputDir, "summary.md"))
+		require.NoError(t, err)
+		require.Equal(t, "# JOHN 19.10.2023\n1. test PR 1 (#123)\n   #123 Add feature (Feature request)\n1. test \\<PR\\> 2 (#124)\n", string(body))
+	})
+
+	t.Run("render text template for every locale", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		templatePath := path.Join(tmpDir, "summary.tmpl")
+		require.NoError(t, os.WriteFile(templatePath, []byte("{{ .Locale }}: {{ .PeriodMonth }}\n"), os.ModePerm))
+		outputDir := path.Join(tmpDir, "out")
+		require.NoError(t, os.Mkdir(outputDir, os.ModePerm))
+
+		output, err := Render(Options{
+			OutputDir:    outputDir,
+			TemplatePath: templatePath,
+			Locales:      []string{"en", "pl"},
+			PeriodFrom:   time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC),
+			PeriodTill:   time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			path.Join(outputDir, "summary_en.txt"),
+			path.Join(outputDir, "summary_pl.txt"),
+		}, output.Files)
+
+		for _, locale := range []string{"en", "pl"} {
+			body, err := os.ReadFile(path.Join(outputDir, "summary_"+locale+".txt"))
+			require.NoError(t, err)
+			require.Contains(t, string(body), locale+": ")
+		}
+	})
+
+	t.Run("invalid text template", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		templatePath := path.Join(tmpDir, "summary.tmpl")
+		require.NoError(t, os.WriteFile(templatePath, []byte("{{ .PeriodFrom "), os.ModePerm))
+		outputDir := path.Join(tmpDir, "out")
+		require.NoError(t, os.Mkdir(outputDir, os.ModePerm))
+
+		_, err := Render(Options{
+			OutputDir:    outputDir,
+			TemplatePath: templatePath,
+			Formats:      []string{FormatMarkdown},
+		})
+		require.ErrorContains(t, err, "failed to parse text template")
+
+		entries, err := os.ReadDir(outputDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("unsupported template", func(t *testing.T) {
+		_, err := Render(Options{
+			OutputDir:    t.TempDir(),
+			TemplatePath: "/templates/report.pages",
+		})
+		require.ErrorContains(t, err, "unsupported template 'report.pages'")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := Render(Options{
+			OutputDir: t.TempDir(),
+			Formats:   []string{"odt"},
+		})
+		require.ErrorContains(t, err, "unsupported report format 'odt'")
+	})
+}
+
+func TestOutputFiles(t *testing.T) {
+	t.Run("list the same files as rendered", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		templatePath := path.Join(tmpDir, "report.md.tmpl")
+		require.NoError(t, os.WriteFile(templatePath, []byte("{{ .PeriodFrom }}"), 0644))
+		outputDir := path.Join(tmpDir, "out")
+		require.NoError(t, os.Mkdir(outputDir, os.ModePerm))
+
+		opts := Options{
+			OutputDir:    outputDir,
+			TemplatePath: templatePath,
+			Formats:      []string{FormatTxt, FormatJSON},
+			Locales:      []string{"en", "pl"},
+			PeriodFrom:   time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC),
+			PeriodTill:   time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC),
+			Results:      testResults,
+		}
+
+		files, err := OutputFiles(opts)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			path.Join(outputDir, "report_en.md"),
+			path.Join(outputDir, "report_en.txt"),
+			path.Join(outputDir, "report_en.json"),
+			path.Join(outputDir, "report_pl.md"),
+			path.Join(outputDir, "report_pl.txt"),
+			path.Join(outputDir, "report_pl.json"),
+		}, files)
+
+		entries, err := os.ReadDir(outputDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+
+		output, err := Render(opts)
+		require.NoError(t, err)
+		require.Equal(t, output.Files, files)
+	})
+
+	t.Run("default report", func(t *testing.T) {
+		files, err := OutputFiles(Options{OutputDir: "/out"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"/out/report.txt"}, files)
+	})
+
+	t.Run("unsupported template", func(t *testing.T) {
+		_, err := OutputFiles(Options{
+			OutputDir:    "/out",
+			TemplatePath: "/templates/report.pages",
+		})
+		require.ErrorContains(t, err, "unsupported template 'report.pages'")
+	})
+
+	t.Run("template output collides with format", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		templatePath := path.Join(tmpDir, "report.txt.tmpl")
+		require.NoError(t, os.WriteFile(templatePath, []byte("{{ .PeriodFrom }}"), 0644))
+
+		_, err := OutputFiles(Options{
+			OutputDir:    "/out",
+			TemplatePath: templatePath,
+			Formats:      []string{FormatMarkdown, FormatTxt},
+		})
+		require.ErrorContains(t, err, "rendered template 'report.txt' would be overwritten by the 'txt' report format")
+	})
+
+	t.Run("template in the output dir", func(t *testing.T) {
+		outputDir := t.TempDir()
+		templatePath := path.Join(outputDir, "summary.md")
+		require.NoError(t, os.WriteFile(templatePath, []byte("{{ .PeriodFrom }}"), 0644))
+
+		_, err := OutputFiles(Options{
+			OutputDir:    outputDir,
+			TemplatePath: templatePath,
+		})
+		require.ErrorContains(t, err, "would be overwritten by the rendered report")
+
+		_, err = Render(Options{
+			OutputDir:    outputDir,
+			TemplatePath: templatePath,
+		})
+		require.ErrorContains(t, err, "would be overwritten by the rendered report")
+
+		body, err := os.ReadFile(templatePath)
+		require.NoError(t, err)
+		require.Equal(t, "{{ .PeriodFrom }}", string(body))
+
+		// locale variants are saved next to the template
+		files, err := OutputFiles(Options{
+			OutputDir:    outputDir,
+			TemplatePath: templatePath,
+			Locales:      []string{"en", "pl"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{path.Join(outputDir, "summary_en.md"), path.Join(outputDir, "summary_pl.md")}, files)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := OutputFiles(Options{
+			OutputDir: "/out",
+			Formats:   []string{"odt"},
+		})
+		require.ErrorContains(t, err, "odt")
+	})
+}
+
+func fixDocxTemplate(t *testing.T, dir string, document string) string {
+	templatePath := path.Join(dir, "template.docx")
+	f, err := os.Create(templatePath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, content := range map[string]string{
+		"word/document.xml":            document,
+		"word/_rels/document.xml.rels": "<Relationships></Relationships>",
+	} {
+		fw, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, w.Close())
+	return templatePath
+}
+
+func fixODTTemplate(t *testing.T, dir string, files map[string]string) string {
+	templatePath := path.Join(dir, "template.odt")
+	f, err := os.Create(templatePath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	mimetype, err := w.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	require.NoError(t, err)
+	_, err = mimetype.Write([]byte("application/vnd.oasis.opendocument.text"))
+	require.NoError(t, err)
+
+	for _, name := range []string{"content.xml", "styles.xml"} {
+		fw, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(files[name]))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, w.Close())
+	return templatePath
+}
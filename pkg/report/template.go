@@ -1,25 +1,107 @@
 package report
 
 import (
+	"bytes"
 	"fmt"
 	"path"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/nguyenthenguyen/docx"
+	"github.com/pPrecel/PKUP/internal/file"
 )
 
 const (
-	DocxPeriodFromTmpl   = "pkupGenPeriodFrom"
-	DocxPeriodTillTmpl   = "pkupGenPeriodTill"
-	DocxApprovalDateTmpl = "pkupGenApprovalDate"
-	DocxResultsTmpl      = "pkupGenResults"
+	DocxPeriodFromTmpl       = "pkupGenPeriodFrom"
+	DocxPeriodTillTmpl       = "pkupGenPeriodTill"
+	DocxApprovalDateTmpl     = "pkupGenApprovalDate"
+	DocxPeriodFromLongTmpl   = "pkupGenPeriodFromLong"
+	DocxPeriodTillLongTmpl   = "pkupGenPeriodTillLong"
+	DocxApprovalDateLongTmpl = "pkupGenApprovalDateLong"
+	DocxPeriodMonthTmpl      = "pkupGenPeriodMonth"
+	DocxResultsTmpl          = "pkupGenResults"
+	DocxCommitCountTmpl      = "pkupGenCommitCount"
+	DocxRepoCountTmpl        = "pkupGenRepoCount"
+	DocxLinesAddedTmpl       = "pkupGenLinesAdded"
+	DocxLinesDeletedTmpl     = "pkupGenLinesDeleted"
+	DocxFilesChangedTmpl     = "pkupGenFilesChanged"
 )
 
 type Values struct {
-	PeriodFrom   string
-	PeriodTill   string
-	ApprovalDate string
-	Result       []string
-	CustomValues map[string]string
+	// locale used to render values ( empty for the default one )
+	Locale       string `json:"locale" yaml:"locale"`
+	Labels       Labels `json:"labels" yaml:"labels"`
+	PeriodFrom   string `json:"periodFrom" yaml:"periodFrom"`
+	PeriodTill   string `json:"periodTill" yaml:"periodTill"`
+	ApprovalDate string `json:"approvalDate" yaml:"approvalDate"`
+	// dates with month names ( e.g. "19 września 2023" )
+	PeriodFromLong   string `json:"periodFromLong" yaml:"periodFromLong"`
+	PeriodTillLong   string `json:"periodTillLong" yaml:"periodTillLong"`
+	ApprovalDateLong string `json:"approvalDateLong" yaml:"approvalDateLong"`
+	// month and year of the period end ( e.g. "październik 2023" )
+	PeriodMonth string       `json:"periodMonth" yaml:"periodMonth"`
+	Result      []string     `json:"result" yaml:"result"`
+	Repos       []RepoValues `json:"repos" yaml:"repos"`
+	// grouping option used to build Groups ( empty means no grouping )
+	GroupBy      string            `json:"groupBy" yaml:"groupBy"`
+	Groups       []GroupValues     `json:"groups" yaml:"groups"`
+	Stats        Stats             `json:"stats" yaml:"stats"`
+	CustomValues map[string]string `json:"customValues" yaml:"customValues"`
+}
+
+type RepoValues struct {
+	Org     string         `json:"org" yaml:"org"`
+	Repo    string         `json:"repo" yaml:"repo"`
+	Commits []CommitValues `json:"commits" yaml:"commits"`
+	Stats   Stats          `json:"stats" yaml:"stats"`
+}
+
+type CommitValues struct {
+	Org     string `json:"org" yaml:"org"`
+	Repo    string `json:"repo" yaml:"repo"`
+	SHA     string `json:"sha" yaml:"sha"`
+	Message string `json:"message" yaml:"message"`
+	// commit message without the first line
+	Body string `json:"body" yaml:"body"`
+	// entry read as a task - the pull request title with titles of closed issues ( the message when not enriched )
+	Task string `json:"task" yaml:"task"`
+	URL  string `json:"url" yaml:"url"`
+	// commit was created by the bot account and flagged
+	Bot bool `json:"bot" yaml:"bot"`
+	// date of the commit authorship
+	Date time.Time `json:"date" yaml:"date"`
+	// path to the .diff artifact relative to the report ( artifacts are saved next to the report )
+	// empty when the artifact was not saved ( e.g. for commits with empty diffs )
+	DiffFile string `json:"diffFile" yaml:"diffFile"`
+	Stats    Stats  `json:"stats" yaml:"stats"`
+	// pull requests associated with the commit ( empty when enrichment is disabled )
+	PullRequests []PullRequestValues `json:"pullRequests" yaml:"pullRequests"`
+}
+
+type PullRequestValues struct {
+	Number int    `json:"number" yaml:"number"`
+	Title  string `json:"title" yaml:"title"`
+	Body   string `json:"body" yaml:"body"`
+	URL    string `json:"url" yaml:"url"`
+	// issues closed by the pull request
+	Issues []IssueValues `json:"issues" yaml:"issues"`
+}
+
+type IssueValues struct {
+	Org    string `json:"org" yaml:"org"`
+	Repo   string `json:"repo" yaml:"repo"`
+	Number int    `json:"number" yaml:"number"`
+	Title  string `json:"title" yaml:"title"`
+	URL    string `json:"url" yaml:"url"`
+}
+
+type Stats struct {
+	Commits      int `json:"commits" yaml:"commits"`
+	Repos        int `json:"repos" yaml:"repos"`
+	Additions    int `json:"additions" yaml:"additions"`
+	Deletions    int `json:"deletions" yaml:"deletions"`
+	FilesChanged int `json:"filesChanged" yaml:"filesChanged"`
 }
 
 type templateRenderer struct {
@@ -38,20 +120,84 @@ func (tr *templateRenderer) RenderToFile(dir, filename string, values Values) er
 		return fmt.Errorf("failed to load docx template: %s", err.Error())
 	}
 
-	resultString := ""
-	for i := range values.Result {
-		resultString += fmt.Sprintf("- %s\n", values.Result[i])
+	docx1 := r.Editable()
+	for _, p := range buildPlaceholders(values) {
+		if err := docx1.Replace(p.key, p.value, -1); err != nil {
+			return fmt.Errorf("failed to replace '%s' placeholder: %s", p.key, err.Error())
+		}
 	}
 
-	docx1 := r.Editable()
-	_ = docx1.Replace(DocxPeriodFromTmpl, values.PeriodFrom, -1)
-	_ = docx1.Replace(DocxPeriodTillTmpl, values.PeriodTill, -1)
-	_ = docx1.Replace(DocxApprovalDateTmpl, values.ApprovalDate, -1)
-	_ = docx1.Replace(DocxResultsTmpl, resultString, -1)
+	// render to memory to never leave the half-written report
+	buf := bytes.NewBuffer(nil)
+	if err := docx1.Write(buf); err != nil {
+		return err
+	}
+
+	return file.WriteAtomic(path.Join(dir, filename), buf.Bytes())
+}
+
+type placeholder struct {
+	key   string
+	value string
+}
+
+// buildPlaceholders returns values for all placeholders supported by templates
+// longer keys go first so placeholders sharing the same prefix are replaced correctly
+func buildPlaceholders(values Values) []placeholder {
+	resultString := buildResultsString(values)
+
+	placeholders := []placeholder{
+		{key: DocxPeriodFromTmpl, value: values.PeriodFrom},
+		{key: DocxPeriodTillTmpl, value: values.PeriodTill},
+		{key: DocxApprovalDateTmpl, value: values.ApprovalDate},
+		{key: DocxPeriodFromLongTmpl, value: values.PeriodFromLong},
+		{key: DocxPeriodTillLongTmpl, value: values.PeriodTillLong},
+		{key: DocxApprovalDateLongTmpl, value: values.ApprovalDateLong},
+		{key: DocxPeriodMonthTmpl, value: values.PeriodMonth},
+		{key: DocxResultsTmpl, value: resultString},
+		{key: DocxCommitCountTmpl, value: strconv.Itoa(values.Stats.Commits)},
+		{key: DocxRepoCountTmpl, value: strconv.Itoa(values.Stats.Repos)},
+		{key: DocxLinesAddedTmpl, value: strconv.Itoa(values.Stats.Additions)},
+		{key: DocxLinesDeletedTmpl, value: strconv.Itoa(values.Stats.Deletions)},
+		{key: DocxFilesChangedTmpl, value: strconv.Itoa(values.Stats.FilesChanged)},
+	}
 
 	for tmpl, val := range values.CustomValues {
-		_ = docx1.Replace(tmpl, val, -1)
+		placeholders = append(placeholders, placeholder{key: tmpl, value: val})
+	}
+
+	sort.SliceStable(placeholders, func(i, j int) bool {
+		if len(placeholders[i].key) != len(placeholders[j].key) {
+			return len(placeholders[i].key) > len(placeholders[j].key)
+		}
+
+		return placeholders[i].key < placeholders[j].key
+	})
+
+	return placeholders
+}
+
+func buildResultsString(values Values) string {
+	resultString := ""
+	if values.GroupBy == "" {
+		for i := range values.Result {
+			resultString += fmt.Sprintf("- %s\n", values.Result[i])
+		}
+
+		return resultString
+	}
+
+	for _, group := range values.Groups {
+		resultString += fmt.Sprintf("%s:\n", group.Title)
+		for _, commit := range group.Commits {
+			if commit.DiffFile == "" {
+				resultString += fmt.Sprintf("  - %s\n", commit.Task)
+				continue
+			}
+
+			resultString += fmt.Sprintf("  - %s (%s)\n", commit.Task, commit.DiffFile)
+		}
 	}
 
-	return docx1.WriteToFile(path.Join(dir, filename))
+	return resultString
 }
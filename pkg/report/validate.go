@@ -0,0 +1,227 @@
+package report
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+const (
+	// report is not rendered when validation finds any issue
+	ValidationModeFail = "fail"
+	// validation issues are returned as warnings
+	ValidationModeWarn = "warn"
+	// validation is skipped
+	ValidationModeOff = "off"
+)
+
+// list of all supported validation modes
+var ValidationModes = []string{
+	ValidationModeFail,
+	ValidationModeWarn,
+	ValidationModeOff,
+}
+
+// matches any built-in or custom placeholder that was not replaced
+var defaultPlaceholderRegex = regexp.MustCompile(`pkupGen[A-Za-z0-9]+`)
+
+var xmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+
+type ValidationOptions struct {
+	// one of ValidationModes ( default: warn )
+	Mode string `json:"mode,omitempty"`
+	// additional regular expressions matching leftover placeholders
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+func ValidateValidationMode(mode string) error {
+	if mode == "" {
+		return nil
+	}
+
+	for _, m := range ValidationModes {
+		if m == mode {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported validation mode '%s' (supported: %s)", mode, strings.Join(ValidationModes, ", "))
+}
+
+// validateTemplate looks for placeholders in the template that are not replaced during rendering and for custom values not used in the template
+// only the template text is scanned so commit messages and other user data never cause issues
+func validateTemplate(opts Options) ([]string, error) {
+	patterns := []*regexp.Regexp{defaultPlaceholderRegex}
+	for _, pattern := range opts.Validation.Patterns {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile validation pattern '%s': %s", pattern, err.Error())
+		}
+
+		patterns = append(patterns, regex)
+	}
+
+	if opts.TemplatePath == "" {
+		// built-in formats don't contain placeholders
+		return []string{}, nil
+	}
+
+	content, text, err := readTemplateText(opts.TemplatePath, opts.CustomValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template '%s': %s", filepath.Base(opts.TemplatePath), err.Error())
+	}
+
+	issues := []string{}
+	for _, leftover := range findLeftovers(text, patterns) {
+		issues = append(issues, fmt.Sprintf("placeholder '%s' was not replaced in '%s'", leftover, filepath.Base(opts.TemplatePath)))
+	}
+
+	for _, key := range findUnusedCustomValues(content, opts.CustomValues) {
+		issues = append(issues, fmt.Sprintf("custom field '%s' is not used in the template '%s'", key, filepath.Base(opts.TemplatePath)))
+	}
+
+	return issues, nil
+}
+
+func findLeftovers(text string, patterns []*regexp.Regexp) []string {
+	found := map[string]struct{}{}
+	for _, pattern := range patterns {
+		for _, match := range pattern.FindAllString(text, -1) {
+			found[match] = struct{}{}
+		}
+	}
+
+	leftovers := []string{}
+	for match := range found {
+		leftovers = append(leftovers, match)
+	}
+	sort.Strings(leftovers)
+
+	return leftovers
+}
+
+func findUnusedCustomValues(content string, customValues map[string]string) []string {
+	unused := []string{}
+	for key := range customValues {
+		if !strings.Contains(content, key) {
+			unused = append(unused, key)
+		}
+	}
+	sort.Strings(unused)
+
+	return unused
+}
+
+// readTemplateText returns raw content of the template and its text left after replacing placeholders
+// markup tags of docx and odt templates and actions of text templates are skipped
+func readTemplateText(path string, customValues map[string]string) (string, string, error) {
+	content, err := readTemplateContent(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	if isDocumentTemplate(path) {
+		// placeholders are replaced in the raw content so ones split by formatting tags are left
+		text := content
+		for _, p := range buildPlaceholders(Values{CustomValues: customValues}) {
+			text = strings.ReplaceAll(text, p.key, "\n")
+		}
+
+		return content, xmlTagRegex.ReplaceAllString(text, ""), nil
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Funcs(textTemplateFuncs).Parse(content)
+	if err != nil {
+		return "", "", err
+	}
+
+	text := []string{}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			text = append(text, templateTextNodes(t.Tree.Root)...)
+		}
+	}
+
+	return content, strings.Join(text, "\n"), nil
+}
+
+func isDocumentTemplate(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".docx" || ext == ".odt"
+}
+
+// templateTextNodes returns literal text of the text template
+func templateTextNodes(node parse.Node) []string {
+	text := []string{}
+	switch n := node.(type) {
+	case *parse.TextNode:
+		text = append(text, string(n.Text))
+	case *parse.ListNode:
+		if n == nil {
+			break
+		}
+		for _, child := range n.Nodes {
+			text = append(text, templateTextNodes(child)...)
+		}
+	case *parse.IfNode:
+		text = append(text, templateTextNodes(n.List)...)
+		text = append(text, templateTextNodes(n.ElseList)...)
+	case *parse.RangeNode:
+		text = append(text, templateTextNodes(n.List)...)
+		text = append(text, templateTextNodes(n.ElseList)...)
+	case *parse.WithNode:
+		text = append(text, templateTextNodes(n.List)...)
+		text = append(text, templateTextNodes(n.ElseList)...)
+	}
+
+	return text
+}
+
+// readTemplateContent returns raw content of all files that can contain placeholders
+func readTemplateContent(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".docx":
+		return readZipContent(path, isDocxContentFile)
+	case ".odt":
+		return readZipContent(path, isODTContentFile)
+	default:
+		data, err := os.ReadFile(path)
+		return string(data), err
+	}
+}
+
+func isDocxContentFile(name string) bool {
+	return name == "word/document.xml" ||
+		strings.HasPrefix(name, "word/header") ||
+		strings.HasPrefix(name, "word/footer")
+}
+
+func readZipContent(path string, filter func(string) bool) (string, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	contents := []string{}
+	for _, f := range r.File {
+		if !filter(f.Name) {
+			continue
+		}
+
+		content, err := readZipFile(f)
+		if err != nil {
+			return "", err
+		}
+
+		contents = append(contents, content)
+	}
+
+	return strings.Join(contents, "\n"), nil
+}
@@ -0,0 +1,134 @@
+package report
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_validation(t *testing.T) {
+	fixOptions := func(t *testing.T, validation ValidationOptions) Options {
+		tmpDir := t.TempDir()
+		templatePath := fixODTTemplate(t, tmpDir, map[string]string{
+			"content.xml": "<office:text><text:p>pkupGenEmployeesName</text:p><text:p>pkup<text:span>GenJobTitle</text:span></text:p><text:p>{{ manager }}</text:p></office:text>",
+			"styles.xml":  "<style:header><text:p>pkupGenPeriodFrom</text:p></style:header>",
+		})
+		outputDir := path.Join(tmpDir, "out")
+		require.NoError(t, os.Mkdir(outputDir, os.ModePerm))
+
+		return Options{
+			OutputDir:    outputDir,
+			TemplatePath: templatePath,
+			PeriodFrom:   time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC),
+			PeriodTill:   time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC),
+			Results:      testResults,
+			CustomValues: map[string]string{
+				"pkupGenEmployeesName": "John Wick",
+				"pkupGenJobTitle":      "Hitman",
+				"pkupGenDepartment":    "Continental",
+			},
+			Validation: validation,
+		}
+	}
+
+	t.Run("warn about leftovers and unused fields", func(t *testing.T) {
+		opts := fixOptions(t, ValidationOptions{
+			Patterns: []string{`\{\{[^}]*\}\}`},
+		})
+
+		output, err := Render(opts)
+		require.NoError(t, err)
+		require.Equal(t, []string{path.Join(opts.OutputDir, "template.odt")}, output.Files)
+		require.Equal(t, []string{
+			"placeholder 'pkupGenJobTitle' was not replaced in 'template.odt'",
+			"placeholder '{{ manager }}' was not replaced in 'template.odt'",
+			"custom field 'pkupGenDepartment' is not used in the template 'template.odt'",
+			// placeholder split by the formatting tag can't be replaced
+			"custom field 'pkupGenJobTitle' is not used in the template 'template.odt'",
+		}, output.Warnings)
+	})
+
+	t.Run("fail on leftovers", func(t *testing.T) {
+		opts := fixOptions(t, ValidationOptions{
+			Mode: ValidationModeFail,
+		})
+
+		output, err := Render(opts)
+		require.ErrorContains(t, err, "report validation failed: placeholder 'pkupGenJobTitle' was not replaced in 'template.odt'")
+		require.Nil(t, output)
+
+		entries, err := os.ReadDir(opts.OutputDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("skip validation", func(t *testing.T) {
+		output, err := Render(fixOptions(t, ValidationOptions{
+			Mode: ValidationModeOff,
+		}))
+		require.NoError(t, err)
+		require.Empty(t, output.Warnings)
+	})
+
+	t.Run("don't scan commits", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		output, err := Render(Options{
+			OutputDir:  tmpDir,
+			Formats:    []string{FormatTxt, FormatMarkdown, FormatJSON},
+			PeriodFrom: time.Date(2023, 9, 19, 0, 0, 0, 0, time.UTC),
+			PeriodTill: time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC),
+			Results:    testResults,
+			Validation: ValidationOptions{
+				Mode:     ValidationModeFail,
+				Patterns: []string{"PR 1"},
+			},
+		})
+		require.NoError(t, err)
+		require.Empty(t, output.Warnings)
+	})
+
+	t.Run("scan text template without actions", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		templatePath := path.Join(tmpDir, "report.md.tmpl")
+		require.NoError(t, os.WriteFile(templatePath, []byte(
+			"# pkupGenTitle {{ .PeriodFrom }}\n{{ range .Result }}- {{ . }}\n{{ else }}TODO{{ end }}",
+		), os.ModePerm))
+		outputDir := path.Join(tmpDir, "out")
+		require.NoError(t, os.Mkdir(outputDir, os.ModePerm))
+
+		output, err := Render(Options{
+			OutputDir:    outputDir,
+			TemplatePath: templatePath,
+			Results:      testResults,
+			Validation: ValidationOptions{
+				Mode:     ValidationModeFail,
+				Patterns: []string{`\{\{[^}]*\}\}`, "TODO"},
+			},
+		})
+		require.ErrorContains(t, err, "report validation failed: placeholder 'TODO' was not replaced in 'report.md.tmpl'; placeholder 'pkupGenTitle' was not replaced in 'report.md.tmpl'")
+		require.Nil(t, output)
+
+		// the failed report is not written
+		entries, err := os.ReadDir(outputDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := Render(fixOptions(t, ValidationOptions{
+			Patterns: []string{"("},
+		}))
+		require.ErrorContains(t, err, "failed to compile validation pattern '('")
+	})
+
+	t.Run("unsupported mode", func(t *testing.T) {
+		_, err := Render(fixOptions(t, ValidationOptions{
+			Mode: "panic",
+		}))
+		require.ErrorContains(t, err, "unsupported validation mode 'panic'")
+	})
+}
@@ -0,0 +1,235 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	go_github "github.com/google/go-github/v53/github"
+	"github.com/pPrecel/PKUP/pkg/github"
+)
+
+var (
+	// e.g.: "Merge pull request #123 from pPrecel/branch", "Merge branch 'main' into feature"
+	mergeMessageRegex = regexp.MustCompile(`^Merge (pull request|branch|remote-tracking branch) `)
+	// e.g.: "This reverts commit 1c1b51c12888f2e8275aa92a48d6fb96fb70d4f3."
+	revertedSHARegex = regexp.MustCompile(`This reverts commit ([0-9a-f]{7,40})`)
+)
+
+// Rule excludes commits matching all its conditions
+type Rule struct {
+	Name        string
+	Message     string
+	Paths       []string
+	BotAuthor   bool
+	Merge       bool
+	RevertPairs bool
+	MinLines    int
+	MaxLines    int
+}
+
+type Excluded struct {
+	Commit *go_github.RepositoryCommit
+	// name of the first matching rule
+	Rule string
+}
+
+type Result struct {
+	Included []*go_github.RepositoryCommit
+	Excluded []Excluded
+}
+
+// Counts returns number of excluded commits per rule name
+func (r *Result) Counts() map[string]int {
+	counts := map[string]int{}
+	for _, excluded := range r.Excluded {
+		counts[excluded.Rule]++
+	}
+
+	return counts
+}
+
+type Filter struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	Rule
+	botLogins []string
+	message   *regexp.Regexp
+	paths     []*regexp.Regexp
+}
+
+// New validates rules and compiles their regular expressions
+// botLogins are additional bot accounts used by the BotAuthor condition
+func New(rules []Rule, botLogins []string) (*Filter, error) {
+	filter := &Filter{}
+	for i, rule := range rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule #%d", i+1)
+		}
+
+		compiled, err := compileRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build rule '%s': %s", rule.Name, err.Error())
+		}
+
+		compiled.botLogins = botLogins
+		filter.rules = append(filter.rules, *compiled)
+	}
+
+	return filter, nil
+}
+
+func compileRule(rule Rule) (*compiledRule, error) {
+	if rule.Message == "" && len(rule.Paths) == 0 && !rule.BotAuthor && !rule.Merge &&
+		!rule.RevertPairs && rule.MinLines == 0 && rule.MaxLines == 0 {
+		return nil, errors.New("rule has no conditions")
+	}
+
+	compiled := &compiledRule{
+		Rule: rule,
+	}
+
+	if rule.Message != "" {
+		regex, err := regexp.Compile(rule.Message)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile message regex: %s", err.Error())
+		}
+
+		compiled.message = regex
+	}
+
+	for _, path := range rule.Paths {
+		regex, err := regexp.Compile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile path regex: %s", err.Error())
+		}
+
+		compiled.paths = append(compiled.paths, regex)
+	}
+
+	return compiled, nil
+}
+
+// Apply splits commits from a single repo into included and excluded ones
+// commits stats and files must be already set ( see github.SetDiffStats )
+func (f *Filter) Apply(commits []*go_github.RepositoryCommit) *Result {
+	result := &Result{
+		Included: []*go_github.RepositoryCommit{},
+		Excluded: []Excluded{},
+	}
+
+	revertPairs := findRevertPairs(commits)
+	for _, commit := range commits {
+		ruleName, excluded := f.match(commit, revertPairs)
+		if excluded {
+			result.Excluded = append(result.Excluded, Excluded{
+				Commit: commit,
+				Rule:   ruleName,
+			})
+			continue
+		}
+
+		result.Included = append(result.Included, commit)
+	}
+
+	return result
+}
+
+func (f *Filter) match(commit *go_github.RepositoryCommit, revertPairs map[string]struct{}) (string, bool) {
+	for _, rule := range f.rules {
+		if rule.matches(commit, revertPairs) {
+			return rule.Name, true
+		}
+	}
+
+	return "", false
+}
+
+func (r *compiledRule) matches(commit *go_github.RepositoryCommit, revertPairs map[string]struct{}) bool {
+	if r.message != nil && !r.message.MatchString(commit.GetCommit().GetMessage()) {
+		return false
+	}
+
+	if len(r.paths) > 0 && !r.matchesAllPaths(commit) {
+		return false
+	}
+
+	if r.BotAuthor && !github.IsBotCommit(commit, r.botLogins) {
+		return false
+	}
+
+	if r.Merge && !isMergeCommit(commit) {
+		return false
+	}
+
+	if r.RevertPairs {
+		if _, ok := revertPairs[commit.GetSHA()]; !ok {
+			return false
+		}
+	}
+
+	if r.MinLines > 0 || r.MaxLines > 0 {
+		lines := commit.GetStats().GetAdditions() + commit.GetStats().GetDeletions()
+		tooSmall := r.MinLines > 0 && lines < r.MinLines
+		tooBig := r.MaxLines > 0 && lines > r.MaxLines
+		if !tooSmall && !tooBig {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesAllPaths returns true if every changed file matches any of the rule paths
+func (r *compiledRule) matchesAllPaths(commit *go_github.RepositoryCommit) bool {
+	if len(commit.Files) == 0 {
+		return false
+	}
+
+	for _, file := range commit.Files {
+		if !matchesAny(r.paths, file.GetFilename()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesAny(regexes []*regexp.Regexp, text string) bool {
+	for _, regex := range regexes {
+		if regex.MatchString(text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isMergeCommit(commit *go_github.RepositoryCommit) bool {
+	return len(commit.Parents) > 1 ||
+		mergeMessageRegex.MatchString(commit.GetCommit().GetMessage())
+}
+
+// findRevertPairs returns SHAs of revert commits and commits reverted by them
+// reverted commit must be on the list to form a pair
+func findRevertPairs(commits []*go_github.RepositoryCommit) map[string]struct{} {
+	pairs := map[string]struct{}{}
+	for _, revert := range commits {
+		match := revertedSHARegex.FindStringSubmatch(revert.GetCommit().GetMessage())
+		if match == nil {
+			continue
+		}
+
+		for _, reverted := range commits {
+			if reverted.GetSHA() != "" && strings.HasPrefix(reverted.GetSHA(), match[1]) {
+				pairs[revert.GetSHA()] = struct{}{}
+				pairs[reverted.GetSHA()] = struct{}{}
+			}
+		}
+	}
+
+	return pairs
+}
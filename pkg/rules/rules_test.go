@@ -0,0 +1,82 @@
+package rules
+
+import (
+	"testing"
+
+	go_github "github.com/google/go-github/v53/github"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func fixCommit(sha, message string, additions, deletions int, files ...string) *go_github.RepositoryCommit {
+	commit := &go_github.RepositoryCommit{
+		SHA: ptr.To(sha),
+		Commit: &go_github.Commit{
+			Message: ptr.To(message),
+		},
+		Stats: &go_github.CommitStats{
+			Additions: ptr.To(additions),
+			Deletions: ptr.To(deletions),
+		},
+	}
+
+	for _, file := range files {
+		commit.Files = append(commit.Files, &go_github.CommitFile{Filename: ptr.To(file)})
+	}
+
+	return commit
+}
+
+func TestFilter_Apply(t *testing.T) {
+	botCommit := fixCommit("sha-bot", "bump lib", 10, 10, "go.mod")
+	botCommit.Author = &go_github.User{Login: ptr.To("dependabot[bot]"), Type: ptr.To("Bot")}
+	mergeCommit := fixCommit("sha-merge", "sync", 10, 10, "main.go")
+	mergeCommit.Parents = []*go_github.Commit{{}, {}}
+
+	commits := []*go_github.RepositoryCommit{
+		fixCommit("sha-feat", "feat: add endpoint", 100, 10, "main.go", "README.md"),
+		fixCommit("sha-deps", "chore(deps): bump lib", 2, 2, "go.mod", "go.sum"),
+		fixCommit("sha-docs", "update docs", 20, 0, "docs/README.md", "CONTRIBUTING.md"),
+		botCommit,
+		mergeCommit,
+		fixCommit("sha-merge-pr", "Merge pull request #1 from user/branch", 10, 10, "main.go"),
+		fixCommit("1c1b51c12888", "fix: something", 10, 10, "main.go"),
+		fixCommit("sha-revert", "Revert \"fix: something\"\n\nThis reverts commit 1c1b51c12888.", 10, 10, "main.go"),
+		fixCommit("sha-small", "typo", 1, 1, "main.go"),
+		fixCommit("sha-big", "generate clients", 6000, 0, "client.go"),
+	}
+
+	filter, err := New([]Rule{
+		{Name: "dependency bumps", Message: `^chore\(deps\)`},
+		{Name: "docs", Paths: []string{`^docs/`, `\.md$`}},
+		{Name: "bots", BotAuthor: true},
+		{Merge: true},
+		{Name: "reverts", RevertPairs: true},
+		{Name: "size", MinLines: 3, MaxLines: 5000},
+	}, nil)
+	require.NoError(t, err)
+
+	result := filter.Apply(commits)
+	require.Len(t, result.Included, 1)
+	require.Equal(t, "sha-feat", result.Included[0].GetSHA())
+	require.Equal(t, map[string]int{
+		"dependency bumps": 1,
+		"docs":             1,
+		"bots":             1,
+		"rule #4":          2,
+		"reverts":          2,
+		"size":             2,
+	}, result.Counts())
+}
+
+func TestNew(t *testing.T) {
+	t.Run("empty rule", func(t *testing.T) {
+		_, err := New([]Rule{{Name: "empty"}}, nil)
+		require.ErrorContains(t, err, "failed to build rule 'empty': rule has no conditions")
+	})
+
+	t.Run("invalid regex", func(t *testing.T) {
+		_, err := New([]Rule{{Message: "("}}, nil)
+		require.ErrorContains(t, err, "failed to build rule 'rule #1': failed to compile message regex")
+	})
+}